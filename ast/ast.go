@@ -3,6 +3,7 @@ package ast
 import (
   "Monkey/token"
   "bytes"
+  "math/big"
   "strings"
 )
 
@@ -19,6 +20,7 @@ import (
 type Node interface {
   TokenLiteral() string
   String()       string
+  Pos()          token.Position  // position of the node's token
 }
 
 type Statement interface {
@@ -43,6 +45,13 @@ func (p *Program) TokenLiteral() string {
   }
 }
 
+func (p *Program) Pos() token.Position {
+  if len(p.Statements) > 0 {
+    return p.Statements[0].Pos()
+  }
+  return token.Position{}
+}
+
 func (p* Program) String() string {
   var out bytes.Buffer
 
@@ -62,6 +71,7 @@ type LetStatement struct {
 
 func (ls *LetStatement) statementNode() {}
 func (ls *LetStatement) TokenLiteral() string  { return ls.Token.Literal }
+func (ls *LetStatement) Pos() token.Position   { return ls.Token.Pos }
 
 func (ls *LetStatement) String() string {
   var out bytes.Buffer
@@ -85,6 +95,7 @@ type Identifier struct {
 // Identifier can possibly be an expression, e.g 'x + x.'
 func (id *Identifier) expressionNode() {}
 func (id *Identifier) TokenLiteral() string  { return id.Token.Literal }
+func (id *Identifier) Pos() token.Position   { return id.Token.Pos }
 
 func (id *Identifier) String() string {
   return id.Value
@@ -101,6 +112,7 @@ type ReturnStatement struct {
 
 func (rs *ReturnStatement) statementNode() {}
 func (rs *ReturnStatement) TokenLiteral() string {return rs.Token.Literal}
+func (rs *ReturnStatement) Pos() token.Position {return rs.Token.Pos}
 
 func (rs *ReturnStatement) String() string {
   var out bytes.Buffer
@@ -111,6 +123,66 @@ func (rs *ReturnStatement) String() string {
   out.WriteString(";")
   return out.String()
 }
+
+// throw <expression>;
+type ThrowStatement struct {
+  Token token.Token // The 'throw' token
+  Value Expression
+}
+
+func (ts *ThrowStatement) statementNode() {}
+func (ts *ThrowStatement) TokenLiteral() string {return ts.Token.Literal}
+func (ts *ThrowStatement) Pos() token.Position {return ts.Token.Pos}
+func (ts *ThrowStatement) String() string {
+  return ts.TokenLiteral() + " " + ts.Value.String() + ";"
+}
+
+// while (<condition>) { <body> }
+type WhileStatement struct {
+  Token     token.Token // The 'while' token
+  Condition Expression
+  Body      *BlockStatement
+}
+
+func (ws *WhileStatement) statementNode() {}
+func (ws *WhileStatement) TokenLiteral() string {return ws.Token.Literal}
+func (ws *WhileStatement) Pos() token.Position {return ws.Token.Pos}
+func (ws *WhileStatement) String() string {
+  return "while" + ws.Condition.String() + " " + ws.Body.String()
+}
+
+// for (<variable> in <iterable>) { <body> }
+type ForStatement struct {
+  Token    token.Token // The 'for' token
+  Variable *Identifier
+  Iterable Expression
+  Body     *BlockStatement
+}
+
+func (fs *ForStatement) statementNode() {}
+func (fs *ForStatement) TokenLiteral() string {return fs.Token.Literal}
+func (fs *ForStatement) Pos() token.Position {return fs.Token.Pos}
+func (fs *ForStatement) String() string {
+  return "for(" + fs.Variable.String() + " in " + fs.Iterable.String() + ") " + fs.Body.String()
+}
+
+type BreakStatement struct {
+  Token token.Token // The 'break' token
+}
+
+func (bs *BreakStatement) statementNode() {}
+func (bs *BreakStatement) TokenLiteral() string {return bs.Token.Literal}
+func (bs *BreakStatement) Pos() token.Position {return bs.Token.Pos}
+func (bs *BreakStatement) String() string {return bs.TokenLiteral() + ";"}
+
+type ContinueStatement struct {
+  Token token.Token // The 'continue' token
+}
+
+func (cs *ContinueStatement) statementNode() {}
+func (cs *ContinueStatement) TokenLiteral() string {return cs.Token.Literal}
+func (cs *ContinueStatement) Pos() token.Position {return cs.Token.Pos}
+func (cs *ContinueStatement) String() string {return cs.TokenLiteral() + ";"}
 /*****   expression statement   *****/
 
 // a statement that consists solely one expression , e.g 'x + 10;'
@@ -122,6 +194,7 @@ type ExpressionStatement struct {
 // since we implement statement interface, ES can be added to Program.Statements 
 func (es *ExpressionStatement) statementNode() {}
 func (es *ExpressionStatement) TokenLiteral() string {return es.Token.Literal}
+func (es *ExpressionStatement) Pos() token.Position {return es.Token.Pos}
 
 func (es *ExpressionStatement) String() string {  
   if es.Expression != nil {
@@ -140,6 +213,7 @@ type PrefixExpression struct {
 
 func (pe *PrefixExpression) expressionNode() {}
 func (pe *PrefixExpression) TokenLiteral() string { return pe.Token.Literal }
+func (pe *PrefixExpression) Pos() token.Position { return pe.Token.Pos }
 func (pe *PrefixExpression) String() string {
 var out bytes.Buffer
   out.WriteString("(")
@@ -149,17 +223,30 @@ var out bytes.Buffer
   return out.String()
 }
 
-/***** Integer/String Literals *****/
+/***** Integer/Float/String Literals *****/
 type IntegerLiteral struct {
   Token token.Token
   Value int64
+  Big   *big.Int  // set instead of Value for literals that overflow an int64
 }
 
 func (il *IntegerLiteral) expressionNode() {}
 func (il *IntegerLiteral) TokenLiteral() string {return il.Token.Literal }
+func (il *IntegerLiteral) Pos() token.Position { return il.Token.Pos }
 func (il *IntegerLiteral) String() string {return il.Token.Literal }
 
 
+type FloatLiteral struct {
+  Token token.Token
+  Value float64
+}
+
+func (fl *FloatLiteral) expressionNode() {}
+func (fl *FloatLiteral) TokenLiteral() string {return fl.Token.Literal }
+func (fl *FloatLiteral) Pos() token.Position { return fl.Token.Pos }
+func (fl *FloatLiteral) String() string {return fl.Token.Literal }
+
+
 type StringLiteral struct {
   Token token.Token
   Value string
@@ -167,8 +254,32 @@ type StringLiteral struct {
 
 func (sl *StringLiteral) expressionNode(){}
 func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Literal }
+func (sl *StringLiteral) Pos() token.Position { return sl.Token.Pos }
 func (sl *StringLiteral) String() string { return sl.Token.Literal }
 
+/* "hello ${name}!" - Parts alternate between the literal chunks of the -..
+* string (*StringLiteral, at even indexes, possibly empty) and the -..
+* interpolated expressions (at odd indexes). */
+type InterpolatedString struct {
+  Token token.Token // The TEMPLATE_HEAD token
+  Parts []Expression
+}
+
+func (is *InterpolatedString) expressionNode(){}
+func (is *InterpolatedString) TokenLiteral() string { return is.Token.Literal }
+func (is *InterpolatedString) Pos() token.Position { return is.Token.Pos }
+func (is *InterpolatedString) String() string {
+  var out bytes.Buffer
+  for i, part := range is.Parts {
+    if i%2 == 0 {
+      out.WriteString(part.String())
+    } else {
+      out.WriteString("${" + part.String() + "}")
+    }
+  }
+  return out.String()
+}
+
 /***** infix expression *****/
 
 type InfixExpression struct {
@@ -180,6 +291,7 @@ type InfixExpression struct {
 
 func (ie *InfixExpression) expressionNode() {}
 func (ie *InfixExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *InfixExpression) Pos() token.Position { return ie.Token.Pos }
 func (ie *InfixExpression) String() string {
   var out bytes.Buffer
   out.WriteString("(")
@@ -199,6 +311,7 @@ type Boolean struct {
 
 func (b *Boolean) expressionNode(){}
 func (b *Boolean) TokenLiteral() string { return b.Token.Literal }
+func (b *Boolean) Pos() token.Position { return b.Token.Pos }
 func (b *Boolean) String() string { return b.Token.Literal }
 
 /***** ifExpression ******/
@@ -212,6 +325,7 @@ type IfExpression struct {
 
 func (ie *IfExpression) expressionNode(){}
 func (ie *IfExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *IfExpression) Pos() token.Position { return ie.Token.Pos }
 func (ie *IfExpression) String() string {
   var out bytes.Buffer
   out.WriteString("if")
@@ -225,20 +339,87 @@ func (ie *IfExpression) String() string {
   return out.String()
 }
 
+/* try { ... } catch (<param>) { ... } finally { ... }, either the -..
+* catch or the finally block may be omitted, not both. */
+type TryExpression struct {
+  Token   token.Token // The 'try' token
+  Block   *BlockStatement
+  Param   *Identifier     // bound to the caught error, may be nil
+  Catch   *BlockStatement // may be nil
+  Finally *BlockStatement // may be nil
+}
+
+func (te *TryExpression) expressionNode(){}
+func (te *TryExpression) TokenLiteral() string { return te.Token.Literal }
+func (te *TryExpression) Pos() token.Position { return te.Token.Pos }
+func (te *TryExpression) String() string {
+  var out bytes.Buffer
+  out.WriteString("try ")
+  out.WriteString(te.Block.String())
+  if te.Catch != nil {
+    out.WriteString(" catch ")
+    if te.Param != nil {
+      out.WriteString("(" + te.Param.String() + ") ")
+    }
+    out.WriteString(te.Catch.String())
+  }
+  if te.Finally != nil {
+    out.WriteString(" finally ")
+    out.WriteString(te.Finally.String())
+  }
+  return out.String()
+}
+
+// <target> = <value>, also += -= *= /=. Target is an identifier or an index expression.
+type AssignExpression struct {
+  Token    token.Token // The assignment operator token
+  Target   Expression
+  Operator string
+  Value    Expression
+}
+
+func (ae *AssignExpression) expressionNode(){}
+func (ae *AssignExpression) TokenLiteral() string { return ae.Token.Literal }
+func (ae *AssignExpression) Pos() token.Position { return ae.Token.Pos }
+func (ae *AssignExpression) String() string {
+  return "(" + ae.Target.String() + " " + ae.Operator + " " + ae.Value.String() + ")"
+}
+
 /****** Functions literals *****/
 type FunctionLiteral struct {
   Token       token.Token // The 'fn' token
   Parameters  []*Identifier
+  Defaults    []Expression  // default value of each parameter, nil when required
+  Rest        *Identifier   // ...rest collects the extra arguments, may be nil
   Body        *BlockStatement
+  Name        string        // set when the literal is bound with let
+}
+
+// number of leading parameters without a default value.
+func (fl *FunctionLiteral) Required() int {
+  for i, d := range fl.Defaults {
+    if d != nil {
+      return i
+    }
+  }
+  return len(fl.Parameters)
 }
 
 func (fl *FunctionLiteral) expressionNode(){}
 func (fl *FunctionLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FunctionLiteral) Pos() token.Position { return fl.Token.Pos }
 func (fl *FunctionLiteral) String() string {
   var out bytes.Buffer
   params := []string{}
-  for _, p := range fl.Parameters {
-    params = append(params, p.String())
+  for i, p := range fl.Parameters {
+    if i < len(fl.Defaults) && fl.Defaults[i] != nil {
+      params = append(params, p.String()+" = "+fl.Defaults[i].String())
+    } else {
+      params = append(params, p.String())
+    }
+  }
+  if fl.Rest != nil {
+    params = append(params, "..."+fl.Rest.String())
   }
 
   out.WriteString(fl.TokenLiteral())
@@ -259,6 +440,7 @@ type CallExpression struct {
 
 func (ce *CallExpression) expressionNode(){}
 func (ce *CallExpression) TokenLiteral() string { return ce.Token.Literal }
+func (ce *CallExpression) Pos() token.Position { return ce.Token.Pos }
 func (ce *CallExpression) String() string {
   var out bytes.Buffer
   args := []string{}
@@ -271,6 +453,88 @@ func (ce *CallExpression) String() string {
   out.WriteString(")")
   return out.String()
 }
+// ...<expression>, expands an array into call arguments or array elements.
+type SpreadExpression struct {
+  Token token.Token // the '...' token
+  Value Expression
+}
+
+func (se *SpreadExpression) expressionNode(){}
+func (se *SpreadExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SpreadExpression) Pos() token.Position { return se.Token.Pos }
+func (se *SpreadExpression) String() string { return "..." + se.Value.String() }
+
+/****** Arrays *****/
+
+// [<expression>, <expression>, ...]
+type ArrayLiteral struct {
+  Token    token.Token // the '[' token
+  Elements []Expression
+}
+
+func (al *ArrayLiteral) expressionNode(){}
+func (al *ArrayLiteral) TokenLiteral() string { return al.Token.Literal }
+func (al *ArrayLiteral) Pos() token.Position { return al.Token.Pos }
+func (al *ArrayLiteral) String() string {
+  var out bytes.Buffer
+  elements := []string{}
+  for _, el := range al.Elements {
+    elements = append(elements, el.String())
+  }
+  out.WriteString("[")
+  out.WriteString(strings.Join(elements, ", "))
+  out.WriteString("]")
+  return out.String()
+}
+
+// <expression>[<expression>]
+type IndexExpression struct {
+  Token token.Token // the '[' token
+  Left  Expression
+  Index Expression
+}
+
+func (ie *IndexExpression) expressionNode(){}
+func (ie *IndexExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *IndexExpression) Pos() token.Position { return ie.Token.Pos }
+func (ie *IndexExpression) String() string {
+  var out bytes.Buffer
+  out.WriteString("(")
+  out.WriteString(ie.Left.String())
+  out.WriteString("[")
+  out.WriteString(ie.Index.String())
+  out.WriteString("])")
+  return out.String()
+}
+
+/****** Hashes *****/
+
+// {<expression>: <expression>, ...}, pairs are kept in source order.
+type HashLiteral struct {
+  Token token.Token // the '{' token
+  Pairs []HashLiteralPair
+}
+
+type HashLiteralPair struct {
+  Key   Expression
+  Value Expression
+}
+
+func (hl *HashLiteral) expressionNode(){}
+func (hl *HashLiteral) TokenLiteral() string { return hl.Token.Literal }
+func (hl *HashLiteral) Pos() token.Position { return hl.Token.Pos }
+func (hl *HashLiteral) String() string {
+  var out bytes.Buffer
+  pairs := []string{}
+  for _, pair := range hl.Pairs {
+    pairs = append(pairs, pair.Key.String()+": "+pair.Value.String())
+  }
+  out.WriteString("{")
+  out.WriteString(strings.Join(pairs, ", "))
+  out.WriteString("}")
+  return out.String()
+}
+
 /****** Expression block statement *****/
 
 type BlockStatement struct {
@@ -280,6 +544,7 @@ type BlockStatement struct {
 
 func (bs *BlockStatement) statementNode(){}
 func (bs *BlockStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BlockStatement) Pos() token.Position { return bs.Token.Pos }
 func (bs *BlockStatement) String() string {
   var out bytes.Buffer
   for _, s := range bs.Statements {
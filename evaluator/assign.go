@@ -0,0 +1,97 @@
+package evaluator
+
+import (
+  "strings"
+  "Monkey/ast"
+  "Monkey/object"
+)
+
+/***** Assignments *****/
+
+/* x = v updates the closest existing binding of x (see -..
+* Environment.Assign), unlike let it never defines one. a[i] = v -..
+* updates an array element or a hash entry in place. The compound forms -..
+* (x += v) combine the current value with v like the infix operator.
+* @return the assigned value. */
+func (e *Evaluator) evalAssignExpression(ae *ast.AssignExpression, env *object.Environment) object.Object {
+  switch target := ae.Target.(type) {
+  case *ast.Identifier:
+    current, ok := env.Get(target.Value)
+    if !ok {
+      return withPos(newError("assignment to undefined identifier: %s", target.Value), target)
+    }
+    value := e.assignedValue(ae, func() object.Object { return current }, env)
+    if isSignal(value) {
+      return value
+    }
+    env.Assign(target.Value, value)
+    return value
+
+  case *ast.IndexExpression:
+    return e.evalIndexAssignment(ae, target, env)
+  }
+  return withPos(newError("cannot assign to %s", ae.Target.String()), ae)
+}
+
+func (e *Evaluator) evalIndexAssignment(ae *ast.AssignExpression, target *ast.IndexExpression, env *object.Environment) object.Object {
+  left := e.eval(target.Left, env)
+  if isSignal(left) {
+    return left
+  }
+  index := e.eval(target.Index, env)
+  if isSignal(index) {
+    return index
+  }
+
+  switch collection := left.(type) {
+  case *object.Array:
+    integer, ok := index.(*object.Integer)
+    if !ok {
+      return withPos(newError("array index must be INTEGER, got %s", index.Type()), target)
+    }
+    // negative indexes count from the end, like in evalArrayIndexExpression.
+    idx := int64Value(integer)
+    length := int64(len(collection.Elements))
+    if idx < 0 {
+      idx += length
+    }
+    if idx < 0 || idx >= length {
+      return withPos(newError("index out of range: %s (length %d)", integer.Inspect(), length), target)
+    }
+    value := e.assignedValue(ae, func() object.Object { return collection.Elements[idx] }, env)
+    if isSignal(value) {
+      return value
+    }
+    collection.Elements[idx] = value
+    return value
+
+  case *object.Hash:
+    key, ok := index.(object.Hashable)
+    if !ok {
+      return withPos(newError("unusable as hash key: %s", index.Type()), target)
+    }
+    value := e.assignedValue(ae, func() object.Object { return evalHashIndexExpression(collection, index) }, env)
+    if isSignal(value) {
+      return value
+    }
+    hashKey := key.HashKey()
+    if _, exists := collection.Pairs[hashKey]; !exists {
+      if err := e.account(hashEntryOverhead); err != nil {
+        return withPos(err, ae)
+      }
+    }
+    collection.Set(hashKey, object.HashPair{Key: index, Value: value})
+    return value
+  }
+  return withPos(newError("index assignment not supported: %s", left.Type()), target)
+}
+
+// the right hand side, combined with the #current value for += -= *= /=.
+func (e *Evaluator) assignedValue(ae *ast.AssignExpression, current func() object.Object, env *object.Environment) object.Object {
+  value := e.eval(ae.Value, env)
+  if isSignal(value) || ae.Operator == "=" {
+    return value
+  }
+  operator := strings.TrimSuffix(ae.Operator, "=")
+  return withPos(e.track(evalInfixExpression(operator, current(), value)), ae)
+}
@@ -0,0 +1,39 @@
+package evaluator
+
+import (
+  "context"
+  "fmt"
+  "time"
+  "Monkey/object"
+)
+
+/***** Execution budget *****/
+
+/* tick spends one step of the budget, it's called at every function -..
+* call and loop iteration so neither an infinite loop nor an infinite -..
+* recursion can run past the budget.
+* @return a BUDGET_ERROR once the budget is exhausted, nil otherwise. */
+func (e *Evaluator) tick() *object.Error {
+  e.steps++
+  if e.MaxSteps > 0 && e.steps > e.MaxSteps {
+    return newBudgetError("step limit exceeded (%d)", e.MaxSteps)
+  }
+  if !e.Deadline.IsZero() && time.Now().After(e.Deadline) {
+    return newBudgetError("deadline exceeded")
+  }
+  if e.ctx != nil {
+    select {
+    case <-e.ctx.Done():
+      if e.ctx.Err() == context.DeadlineExceeded {
+        return newBudgetError("deadline exceeded")
+      }
+      return newBudgetError("evaluation canceled")
+    default:
+    }
+  }
+  return nil
+}
+
+func newBudgetError(format string, a ...interface{}) *object.Error {
+  return &object.Error{Message: fmt.Sprintf(format, a...), Kind: object.BUDGET_ERROR}
+}
@@ -1,6 +1,22 @@
 package evaluator
 
-import "Monkey/object"
+import (
+  "fmt"
+  "math"
+  "math/big"
+  "strconv"
+  "strings"
+  "unicode/utf8"
+  "Monkey/object"
+)
+
+// command line arguments of the running script, argv[0] is the script name.
+var scriptArgs = []string{}
+
+// SetArgs exposes #args to the program through the argc/argv builtins.
+func SetArgs(args []string) {
+  scriptArgs = args
+}
 
 var builtins = map[string]*object.Builtin{
   "len": &object.Builtin{
@@ -11,10 +27,383 @@ var builtins = map[string]*object.Builtin{
 
       switch arg := args[0].(type) {
       case *object.String:
-        return &object.Integer{Value: int64(len(arg.Value))}
+        return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
+      case *object.Array:
+        return &object.Integer{Value: int64(len(arg.Elements))}
+      case *object.Hash:
+        return &object.Integer{Value: int64(len(arg.Keys))}
       default:
         return newError("argument to `len` not supported, got %s",args[0].Type())
       }
     },
   },
+  "puts": &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      for _, arg := range args {
+        fmt.Println(arg.Inspect())
+      }
+      return NULL
+    },
+  },
+  "argc": &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      if len(args) != 0 {
+        return newError("wrong number of arguments. got=%d, want=0",len(args))
+      }
+      return &object.Integer{Value: int64(len(scriptArgs))}
+    },
+  },
+  "argv": &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      if len(args) != 1 {
+        return newError("wrong number of arguments. got=%d, want=1",len(args))
+      }
+      idx, ok := args[0].(*object.Integer)
+      if !ok {
+        return newError("argument to `argv` must be INTEGER, got %s",args[0].Type())
+      }
+      i := int64Value(idx)
+      if i < 0 || i >= int64(len(scriptArgs)) {
+        return NULL
+      }
+      return &object.String{Value: scriptArgs[i]}
+    },
+  },
+  "args": &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      if len(args) != 0 {
+        return newError("wrong number of arguments. got=%d, want=0",len(args))
+      }
+      elements := make([]object.Object, len(scriptArgs))
+      for i, arg := range scriptArgs {
+        elements[i] = &object.String{Value: arg}
+      }
+      return &object.Array{Elements: elements}
+    },
+  },
+
+  /***** Arrays, builtins never modify the array they're given *****/
+
+  "first": &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      if len(args) != 1 {
+        return newError("wrong number of arguments. got=%d, want=1",len(args))
+      }
+      array, ok := args[0].(*object.Array)
+      if !ok {
+        return newError("argument to `first` must be ARRAY, got %s",args[0].Type())
+      }
+      if len(array.Elements) > 0 {
+        return array.Elements[0]
+      }
+      return NULL
+    },
+  },
+  "last": &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      if len(args) != 1 {
+        return newError("wrong number of arguments. got=%d, want=1",len(args))
+      }
+      array, ok := args[0].(*object.Array)
+      if !ok {
+        return newError("argument to `last` must be ARRAY, got %s",args[0].Type())
+      }
+      if length := len(array.Elements); length > 0 {
+        return array.Elements[length-1]
+      }
+      return NULL
+    },
+  },
+  "rest": &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      if len(args) != 1 {
+        return newError("wrong number of arguments. got=%d, want=1",len(args))
+      }
+      array, ok := args[0].(*object.Array)
+      if !ok {
+        return newError("argument to `rest` must be ARRAY, got %s",args[0].Type())
+      }
+      length := len(array.Elements)
+      if length == 0 {
+        return NULL
+      }
+      newElements := make([]object.Object, length-1)
+      copy(newElements, array.Elements[1:length])
+      return &object.Array{Elements: newElements}
+    },
+  },
+  "push": &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      if len(args) != 2 {
+        return newError("wrong number of arguments. got=%d, want=2",len(args))
+      }
+      array, ok := args[0].(*object.Array)
+      if !ok {
+        return newError("argument to `push` must be ARRAY, got %s",args[0].Type())
+      }
+      length := len(array.Elements)
+      newElements := make([]object.Object, length+1)
+      copy(newElements, array.Elements)
+      newElements[length] = args[1]
+      return &object.Array{Elements: newElements}
+    },
+  },
+  // slice(array, start[, end]), negative bounds count from the end.
+  "slice": &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      if len(args) != 2 && len(args) != 3 {
+        return newError("wrong number of arguments. got=%d, want=2 or 3",len(args))
+      }
+      var length int64
+      var runes []rune
+      switch arg := args[0].(type) {
+      case *object.Array:
+        length = int64(len(arg.Elements))
+      case *object.String:
+        runes = []rune(arg.Value)
+        length = int64(len(runes))
+      default:
+        return newError("argument to `slice` must be ARRAY or STRING, got %s",args[0].Type())
+      }
+      start, end := int64(0), length
+      for i, arg := range args[1:] {
+        bound, ok := arg.(*object.Integer)
+        if !ok {
+          return newError("bounds of `slice` must be INTEGER, got %s",arg.Type())
+        }
+        if i == 0 {
+          start = clampIndex(int64Value(bound), length)
+        } else {
+          end = clampIndex(int64Value(bound), length)
+        }
+      }
+      if start > end {
+        start = end
+      }
+      if args[0].Type() == object.STRING_OBJ {
+        return &object.String{Value: string(runes[start:end])}
+      }
+      newElements := make([]object.Object, end-start)
+      copy(newElements, args[0].(*object.Array).Elements[start:end])
+      return &object.Array{Elements: newElements}
+    },
+  },
+  "concat": &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      newElements := []object.Object{}
+      for _, arg := range args {
+        array, ok := arg.(*object.Array)
+        if !ok {
+          return newError("arguments to `concat` must be ARRAY, got %s",arg.Type())
+        }
+        newElements = append(newElements, array.Elements...)
+      }
+      return &object.Array{Elements: newElements}
+    },
+  },
+
+  /***** Strings, len/indexing/slice count characters (runes) *****/
+
+  // bytes(s) is the UTF-8 encoding of #s, for byte-level work.
+  "bytes": &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      if len(args) != 1 {
+        return newError("wrong number of arguments. got=%d, want=1",len(args))
+      }
+      str, ok := args[0].(*object.String)
+      if !ok {
+        return newError("argument to `bytes` must be STRING, got %s",args[0].Type())
+      }
+      elements := make([]object.Object, len(str.Value))
+      for i := 0; i < len(str.Value); i++ {
+        elements[i] = &object.Integer{Value: int64(str.Value[i])}
+      }
+      return &object.Array{Elements: elements}
+    },
+  },
+
+  /***** Hashes, builtins never modify the hash they're given *****/
+
+  "keys": &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      if len(args) != 1 {
+        return newError("wrong number of arguments. got=%d, want=1",len(args))
+      }
+      hash, ok := args[0].(*object.Hash)
+      if !ok {
+        return newError("argument to `keys` must be HASH, got %s",args[0].Type())
+      }
+      elements := make([]object.Object, 0, len(hash.Keys))
+      for _, key := range hash.Keys {
+        elements = append(elements, hash.Pairs[key].Key)
+      }
+      return &object.Array{Elements: elements}
+    },
+  },
+  "values": &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      if len(args) != 1 {
+        return newError("wrong number of arguments. got=%d, want=1",len(args))
+      }
+      hash, ok := args[0].(*object.Hash)
+      if !ok {
+        return newError("argument to `values` must be HASH, got %s",args[0].Type())
+      }
+      elements := make([]object.Object, 0, len(hash.Keys))
+      for _, key := range hash.Keys {
+        elements = append(elements, hash.Pairs[key].Value)
+      }
+      return &object.Array{Elements: elements}
+    },
+  },
+  "has": &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      if len(args) != 2 {
+        return newError("wrong number of arguments. got=%d, want=2",len(args))
+      }
+      hash, ok := args[0].(*object.Hash)
+      if !ok {
+        return newError("argument to `has` must be HASH, got %s",args[0].Type())
+      }
+      key, ok := args[1].(object.Hashable)
+      if !ok {
+        return newError("unusable as hash key: %s",args[1].Type())
+      }
+      _, exists := hash.Pairs[key.HashKey()]
+      return nativeBoolToBooleanObject(exists)
+    },
+  },
+  "delete": &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      if len(args) != 2 {
+        return newError("wrong number of arguments. got=%d, want=2",len(args))
+      }
+      hash, ok := args[0].(*object.Hash)
+      if !ok {
+        return newError("argument to `delete` must be HASH, got %s",args[0].Type())
+      }
+      key, ok := args[1].(object.Hashable)
+      if !ok {
+        return newError("unusable as hash key: %s",args[1].Type())
+      }
+      newHash := copyHash(hash)
+      newHash.Delete(key.HashKey())
+      return newHash
+    },
+  },
+  // merge(a, b, ...), keys of later hashes win.
+  "merge": &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      newHash := object.NewHash()
+      for _, arg := range args {
+        hash, ok := arg.(*object.Hash)
+        if !ok {
+          return newError("arguments to `merge` must be HASH, got %s",arg.Type())
+        }
+        for _, key := range hash.Keys {
+          newHash.Set(key, hash.Pairs[key])
+        }
+      }
+      return newHash
+    },
+  },
+
+  /***** Numbers *****/
+
+  "int": &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      if len(args) != 1 {
+        return newError("wrong number of arguments. got=%d, want=1",len(args))
+      }
+      switch arg := args[0].(type) {
+      case *object.Integer:
+        return arg
+      case *object.Float:
+        return floatToInteger("int", math.Trunc(arg.Value))
+      case *object.String:
+        value, ok := new(big.Int).SetString(strings.TrimSpace(arg.Value), 10)
+        if !ok {
+          return newError("could not convert %q to INTEGER", arg.Value)
+        }
+        return object.NewBigInteger(value)
+      default:
+        return newError("argument to `int` not supported, got %s",args[0].Type())
+      }
+    },
+  },
+  "float": &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      if len(args) != 1 {
+        return newError("wrong number of arguments. got=%d, want=1",len(args))
+      }
+      switch arg := args[0].(type) {
+      case *object.Integer, *object.Float:
+        return &object.Float{Value: toFloat(arg)}
+      case *object.String:
+        value, err := strconv.ParseFloat(strings.TrimSpace(arg.Value), 64)
+        if err != nil {
+          return newError("could not convert %q to FLOAT", arg.Value)
+        }
+        return &object.Float{Value: value}
+      default:
+        return newError("argument to `float` not supported, got %s",args[0].Type())
+      }
+    },
+  },
+  "round": roundingBuiltin("round", math.Round),
+  "floor": roundingBuiltin("floor", math.Floor),
+  "ceil":  roundingBuiltin("ceil", math.Ceil),
+}
+
+// round/floor/ceil turn a float into an integer, integers are returned as is.
+func roundingBuiltin(name string, fn func(float64) float64) *object.Builtin {
+  return &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      if len(args) != 1 {
+        return newError("wrong number of arguments. got=%d, want=1",len(args))
+      }
+      switch arg := args[0].(type) {
+      case *object.Integer:
+        return arg
+      case *object.Float:
+        return floatToInteger(name, fn(arg.Value))
+      default:
+        return newError("argument to `%s` must be INTEGER or FLOAT, got %s",name, args[0].Type())
+      }
+    },
+  }
+}
+
+// #value must already be a whole number, large values become big integers.
+func floatToInteger(name string, value float64) object.Object {
+  if math.IsNaN(value) || math.IsInf(value, 0) {
+    return newError("`%s` cannot convert %s to INTEGER", name, (&object.Float{Value: value}).Inspect())
+  }
+  if value >= math.MinInt64 && value < math.MaxInt64 {
+    return &object.Integer{Value: int64(value)}
+  }
+  integer, _ := big.NewFloat(value).Int(nil)
+  return object.NewBigInteger(integer)
+}
+
+func copyHash(hash *object.Hash) *object.Hash {
+  newHash := object.NewHash()
+  for _, key := range hash.Keys {
+    newHash.Set(key, hash.Pairs[key])
+  }
+  return newHash
+}
+
+// resolves a negative #idx from the end and clamps it into [0, length].
+func clampIndex(idx int64, length int64) int64 {
+  if idx < 0 {
+    idx += length
+  }
+  if idx < 0 {
+    return 0
+  }
+  if idx > length {
+    return length
+  }
+  return idx
 }
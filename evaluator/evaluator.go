@@ -1,9 +1,14 @@
 package evaluator
 
 import (
+  "context"
   "fmt"
+  "math"
+  "math/big"
+  "strings"
   "Monkey/ast"
   "Monkey/object"
+  "time"
   )
 
 var (
@@ -13,49 +18,151 @@ var (
   FALSE = &object.Boolean{Value: false}
 )
 
+// value of the parameters whose default isn't evaluated yet, see extendFunctionEnv.
+const UNINITIALIZED_OBJ = "UNINITIALIZED"
+
+type uninitialized struct{}
+
+func (u *uninitialized) Type() object.ObjectType { return UNINITIALIZED_OBJ }
+func (u *uninitialized) Inspect() string          { return "uninitialized" }
+
+var UNINITIALIZED = &uninitialized{}
+
+// default limit of nested (non tail) function calls.
+const DEFAULT_MAX_DEPTH = 10000
+
+/* Evaluator holds the configuration and the state of one evaluation, -..
+* it must not be shared between goroutines. */
+type Evaluator struct {
+  // nested function calls allowed before "maximum recursion depth -..
+  // exceeded", 0 means no limit. Deep recursion otherwise exhausts the -..
+  // Go stack, which kills the whole process.
+  MaxDepth int
+
+  // execution budget, checked at every call and loop iteration (see -..
+  // budget.go). It's spent over the lifetime of the Evaluator.
+  MaxSteps int        // 0 means no limit
+  Deadline time.Time  // zero means no deadline
+
+  // cap in bytes on the estimated size of the strings, numbers and -..
+  // collections created by the program (see memory.go), 0 means no limit.
+  // It caps the total allocations over the lifetime of the Evaluator, -..
+  // not the live memory: garbage is never subtracted, so a long loop -..
+  // building short-lived strings or collections runs out of it too. -..
+  // Literals and fixed-size numbers aren't charged.
+  MaxMemory int64
+
+  depth     int               // current number of nested function calls
+  steps     int               // calls and loop iterations executed so far
+  allocated int64             // estimated bytes allocated so far
+  ctx       context.Context   // set by EvalContext, may be nil
+  builtinSite *ast.CallExpression // call of the running builtin, positions its panics
+  node      ast.Node          // innermost node being evaluated, positions other panics
+}
+
+func New() *Evaluator {
+  return &Evaluator{MaxDepth: DEFAULT_MAX_DEPTH}
+}
+
+// Eval evaluates #node with a fresh Evaluator and the default limits.
+func Eval(node ast.Node, env *object.Environment) object.Object {
+  return New().Eval(node, env)
+}
+
+/* EvalContext is like Eval but stops with a BUDGET_ERROR once #ctx is -..
+* canceled or its deadline passes. */
+func (e *Evaluator) EvalContext(ctx context.Context, node ast.Node, env *object.Environment) object.Object {
+  e.ctx = ctx
+  defer func() { e.ctx = nil }()
+  return e.Eval(node, env)
+}
+
 // Initiates Eval with all program statements.
-func evalProgram(program *ast.Program, env *object.Environment) object.Object {
+func (e *Evaluator) evalProgram(program *ast.Program, env *object.Environment) object.Object {
   var result object.Object
   for _, statement := range program.Statements {
-    result = Eval(statement, env)
+    result = e.eval(statement, env)
 
     switch result := result.(type) {
       case *object.ReturnValue:
         return result.Value
       case *object.Error:
         return result
+      case *object.Break, *object.Continue:
+        return loopControlError(result)
       }
     }
   return result
 }
 
-func Eval(node ast.Node, env *object.Environment) object.Object {
+/* Eval evaluates #node in #env. A Go panic must never take down the -..
+* host (e.g the REPL session), it's turned into an error of kind -..
+* object.INTERNAL_ERROR, positioned at the call when a builtin panicked -..
+* and at the innermost node being evaluated otherwise. */
+func (e *Evaluator) Eval(node ast.Node, env *object.Environment) (result object.Object) {
+  defer func() {
+    if r := recover(); r != nil {
+      err := newError("internal error: %v", r)
+      err.Kind = object.INTERNAL_ERROR
+      if e.builtinSite != nil {
+        withPos(err, e.builtinSite)
+      } else if e.node != nil {
+        withPos(err, e.node)
+      }
+      e.builtinSite = nil
+      e.node = nil
+      result = err
+    }
+  }()
+  return e.eval(node, env)
+}
+
+// keeps track of the innermost node for Eval's recover, see evalNode.
+func (e *Evaluator) eval(node ast.Node, env *object.Environment) object.Object {
+  outer := e.node
+  e.node = node
+  result := e.evalNode(node, env)
+  e.node = outer
+  return result
+}
+
+func (e *Evaluator) evalNode(node ast.Node, env *object.Environment) object.Object {
   switch nodeType := node.(type) {
 
   // Statements
   case *ast.Program:
-    return evalProgram(nodeType, env)
+    return e.evalProgram(nodeType, env)
 
   case *ast.LetStatement:
-    val := Eval(nodeType.Value, env)
-    if isError(val) {
+    val := e.eval(nodeType.Value, env)
+    if isSignal(val) {
       return val
     }
     env.Set(nodeType.Name.Value, val)
 
   case *ast.ExpressionStatement:
-    return Eval(nodeType.Expression, env)
+    return e.eval(nodeType.Expression, env)
 
   case *ast.BlockStatement:
-    return evalBlockStatement(nodeType, env)
+    return e.evalBlockStatement(nodeType, env)
 
   // Expressions
+  // literals aren't charged to MaxMemory, their size is bound by the source.
   case *ast.IntegerLiteral:
+    if nodeType.Big != nil {
+      return &object.Integer{Big: nodeType.Big}
+    }
     return &object.Integer{Value: nodeType.Value}
 
+  case *ast.FloatLiteral:
+    return &object.Float{Value: nodeType.Value}
+
   case *ast.StringLiteral:
     return &object.String{Value: nodeType.Value}
 
+  case *ast.InterpolatedString:
+    return e.evalInterpolatedString(nodeType, env)
+
   case *ast.Identifier:
     return evalIdentifier(nodeType, env)
 
@@ -63,29 +170,57 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
     return nativeBoolToBooleanObject(nodeType.Value)
 
   case *ast.PrefixExpression:
-    right := Eval(nodeType.Right, env)
-    if isError(right) {
+    right := e.eval(nodeType.Right, env)
+    if isSignal(right) {
       return right
     }
-    return evalPrefixExpression(nodeType.Operator, right)
+    return withPos(e.track(evalPrefixExpression(nodeType.Operator, right)), nodeType)
 
   case *ast.InfixExpression:
-    right := Eval(nodeType.Right, env)
-    if isError(right){
+    if nodeType.Operator == "&&" || nodeType.Operator == "||" {
+      return e.evalLogicalExpression(nodeType, env)
+    }
+    right := e.eval(nodeType.Right, env)
+    if isSignal(right){
       return right
     }
-    left := Eval(nodeType.Left, env)
-    if isError(left){
+    left := e.eval(nodeType.Left, env)
+    if isSignal(left){
       return left
     }
-    return evalInfixExpression(nodeType.Operator, left, right)
+    return withPos(e.track(evalInfixExpression(nodeType.Operator, left, right)), nodeType)
 
   case *ast.IfExpression:
-    return evalIfExpression(nodeType, env)
+    return e.evalIfExpression(nodeType, env)
+
+  case *ast.AssignExpression:
+    return e.evalAssignExpression(nodeType, env)
+
+  case *ast.TryExpression:
+    return e.evalTryExpression(nodeType, env)
+
+  case *ast.WhileStatement:
+    return e.evalWhileStatement(nodeType, env)
+
+  case *ast.ForStatement:
+    return e.evalForStatement(nodeType, env)
+
+  case *ast.BreakStatement:
+    return &object.Break{Pos: nodeType.Pos()}
+
+  case *ast.ContinueStatement:
+    return &object.Continue{Pos: nodeType.Pos()}
+
+  case *ast.ThrowStatement:
+    val := e.eval(nodeType.Value, env)
+    if isSignal(val) {
+      return val
+    }
+    return withPos(newThrownError(val), nodeType)
 
   case *ast.ReturnStatement:
-    val := Eval(nodeType.ReturnValue, env)
-    if isError(val){
+    val := e.eval(nodeType.ReturnValue, env)
+    if isSignal(val){
       return val
     }
     return &object.ReturnValue{Value: val}
@@ -93,18 +228,46 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
   case *ast.FunctionLiteral:
     params := nodeType.Parameters
     body := nodeType.Body
-    return &object.Function{Parameters: params, Env: env, Body: body}
+    return &object.Function{
+      Parameters: params,
+      Defaults:   nodeType.Defaults,
+      Rest:       nodeType.Rest,
+      Env:        env,
+      Body:       body,
+      Name:       nodeType.Name,
+    }
 
   case *ast.CallExpression:
-    function := Eval(nodeType.Function, env)
-    if isError(function) {
+    function := e.eval(nodeType.Function, env)
+    if isSignal(function) {
       return function
     }
-    args := evalExpressions(nodeType.Arguments, env)
-    if len(args) == 1 && isError(args[0]) {
+    args := e.evalExpressions(nodeType.Arguments, env)
+    if len(args) == 1 && isSignal(args[0]) {
       return args[0]
     }
-    return applyFunction(function, args)
+    return e.applyFunction(function, args, nodeType)
+
+  case *ast.ArrayLiteral:
+    elements := e.evalExpressions(nodeType.Elements, env)
+    if len(elements) == 1 && isSignal(elements[0]) {
+      return elements[0]
+    }
+    return withPos(e.track(&object.Array{Elements: elements}), nodeType)
+
+  case *ast.HashLiteral:
+    return withPos(e.track(e.evalHashLiteral(nodeType, env)), nodeType)
+
+  case *ast.IndexExpression:
+    left := e.eval(nodeType.Left, env)
+    if isSignal(left) {
+      return left
+    }
+    index := e.eval(nodeType.Index, env)
+    if isSignal(index) {
+      return index
+    }
+    return withPos(evalIndexExpression(left, index), nodeType)
   }
   return nil
 }
@@ -131,7 +294,13 @@ func evalBangOperatorExpression(right object.Object) object.Object {
       return TRUE
 
     case *object.Integer:
-     if (right.Value == 0){
+     if (!right.IsBig() && right.Value == 0){
+        return TRUE
+      }
+      return FALSE
+
+    case *object.Float:
+      if right.Value == 0 {
         return TRUE
       }
       return FALSE
@@ -145,20 +314,33 @@ func evalBangOperatorExpression(right object.Object) object.Object {
 }
 
 func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
-  if right.Type() != object.INTEGER_OBJ {
-    return newError("unknown operator: -%s", right.Type())
+  switch right := right.(type) {
+    case *object.Integer:
+      if right.IsBig() || right.Value == math.MinInt64 {
+        return object.NewBigInteger(new(big.Int).Neg(right.BigInt()))
+      }
+      return &object.Integer{Value: -right.Value}
+    case *object.Float:
+      return &object.Float{Value: -right.Value}
+    default:
+      return newError("unknown operator: -%s", right.Type())
   }
-  value := right.(*object.Integer).Value
-  return &object.Integer{Value: -value}
 }
 
 /****** Infix Expressions ******/
 
 func evalInfixExpression(operator string, left object.Object, right object.Object) object.Object {
   switch {
+  case operator == ".." || operator == "..<":
+    return evalRangeExpression(operator, left, right)
+
   case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
     return evalIntegerInfixExpression(operator, left, right)
 
+  // mixed integer/float arithmetic promotes the integer to a float.
+  case isNumber(left) && isNumber(right):
+    return evalFloatInfixExpression(operator, left, right)
+
   case operator == "==":
     return nativeBoolToBooleanObject(left == right)
 
@@ -188,22 +370,220 @@ func evalStringInfixExpression(operator string, left, right object.Object) objec
   return &object.String{Value: leftVal + rightVal}
 }
 
+/* int64 arithmetic is used as long as it doesn't overflow, otherwise -..
+* the operation is redone with math/big. */
 func evalIntegerInfixExpression(operator string, left object.Object, right object.Object) object.Object {
-  leftVal := left.(*object.Integer).Value
-  rightVal := right.(*object.Integer).Value
+  leftInt := left.(*object.Integer)
+  rightInt := right.(*object.Integer)
+  if leftInt.IsBig() || rightInt.IsBig() {
+    return evalBigIntegerInfixExpression(operator, leftInt, rightInt)
+  }
+
+  leftVal := leftInt.Value
+  rightVal := rightInt.Value
   switch operator {
     case "+":
-      return &object.Integer{Value: leftVal + rightVal}
+      sum := leftVal + rightVal
+      if (leftVal > 0 && rightVal > 0 && sum < 0) || (leftVal < 0 && rightVal < 0 && sum >= 0) {
+        return evalBigIntegerInfixExpression(operator, leftInt, rightInt)
+      }
+      return &object.Integer{Value: sum}
     case "-":
-      return &object.Integer{Value: leftVal - rightVal}
+      diff := leftVal - rightVal
+      if (leftVal >= 0 && rightVal < 0 && diff < 0) || (leftVal < 0 && rightVal > 0 && diff >= 0) {
+        return evalBigIntegerInfixExpression(operator, leftInt, rightInt)
+      }
+      return &object.Integer{Value: diff}
     case "*":
-      return &object.Integer{Value: leftVal * rightVal}
+      product := leftVal * rightVal
+      if leftVal != 0 && (product / leftVal != rightVal || (leftVal == -1 && rightVal == math.MinInt64)) {
+        return evalBigIntegerInfixExpression(operator, leftInt, rightInt)
+      }
+      return &object.Integer{Value: product}
     case "/":
+      if rightVal == 0 {
+        return newError("division by zero")
+      }
+      if leftVal == math.MinInt64 && rightVal == -1 {
+        return evalBigIntegerInfixExpression(operator, leftInt, rightInt)
+      }
       return &object.Integer{Value: leftVal / rightVal}
     case "<":
       return nativeBoolToBooleanObject(leftVal < rightVal)
     case ">":
       return nativeBoolToBooleanObject(leftVal > rightVal)
+    case "<=":
+      return nativeBoolToBooleanObject(leftVal <= rightVal)
+    case ">=":
+      return nativeBoolToBooleanObject(leftVal >= rightVal)
+    case "==":
+      return nativeBoolToBooleanObject(leftVal == rightVal)
+    case "!=":
+      return nativeBoolToBooleanObject(leftVal != rightVal)
+    default:
+     return newError("unknown operator: %s %s %s",
+      left.Type(), operator, right.Type())
+  }
+}
+
+/***** Index expressions *****/
+
+func evalIndexExpression(left object.Object, index object.Object) object.Object {
+  switch {
+  case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
+    return evalArrayIndexExpression(left, index)
+  case left.Type() == object.STRING_OBJ && index.Type() == object.INTEGER_OBJ:
+    return evalStringIndexExpression(left, index)
+  case left.Type() == object.HASH_OBJ:
+    return evalHashIndexExpression(left, index)
+  default:
+    return newError("index operator not supported: %s[%s]", left.Type(), index.Type())
+  }
+}
+
+// negative indexes count from the end, out of range indexes evaluate to NULL.
+func evalArrayIndexExpression(array object.Object, index object.Object) object.Object {
+  elements := array.(*object.Array).Elements
+  idx := int64Value(index.(*object.Integer))
+  max := int64(len(elements))
+
+  if idx < 0 {
+    idx += max
+  }
+  if idx < 0 || idx >= max {
+    return NULL
+  }
+  return elements[idx]
+}
+
+// strings are indexed by character (rune) like arrays, see the bytes builtin.
+func evalStringIndexExpression(str object.Object, index object.Object) object.Object {
+  runes := []rune(str.(*object.String).Value)
+  idx := int64Value(index.(*object.Integer))
+  max := int64(len(runes))
+
+  if idx < 0 {
+    idx += max
+  }
+  if idx < 0 || idx >= max {
+    return NULL
+  }
+  return &object.String{Value: string(runes[idx])}
+}
+
+// missing keys evaluate to NULL.
+func evalHashIndexExpression(hash object.Object, index object.Object) object.Object {
+  hashObject := hash.(*object.Hash)
+  key, ok := index.(object.Hashable)
+  if !ok {
+    return newError("unusable as hash key: %s", index.Type())
+  }
+  pair, ok := hashObject.Pairs[key.HashKey()]
+  if !ok {
+    return NULL
+  }
+  return pair.Value
+}
+
+/***** Strings *****/
+
+// the interpolated values are turned into strings with their Inspect().
+func (e *Evaluator) evalInterpolatedString(node *ast.InterpolatedString, env *object.Environment) object.Object {
+  var out strings.Builder
+  for i, part := range node.Parts {
+    if literal, ok := part.(*ast.StringLiteral); ok && i%2 == 0 {
+      out.WriteString(literal.Value)
+      continue
+    }
+    value := e.eval(part, env)
+    if isSignal(value) {
+      return value
+    }
+    if value == nil {
+      value = NULL
+    }
+    out.WriteString(value.Inspect())
+  }
+  return withPos(e.track(&object.String{Value: out.String()}), node)
+}
+
+/***** Hashes *****/
+
+func (e *Evaluator) evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object {
+  hash := object.NewHash()
+
+  for _, pairNode := range node.Pairs {
+    key := e.eval(pairNode.Key, env)
+    if isSignal(key) {
+      return key
+    }
+    hashKey, ok := key.(object.Hashable)
+    if !ok {
+      return withPos(newError("unusable as hash key: %s", key.Type()), pairNode.Key)
+    }
+    value := e.eval(pairNode.Value, env)
+    if isSignal(value) {
+      return value
+    }
+    hash.Set(hashKey.HashKey(), object.HashPair{Key: key, Value: value})
+  }
+  return hash
+}
+
+func evalBigIntegerInfixExpression(operator string, left *object.Integer, right *object.Integer) object.Object {
+  leftVal := left.BigInt()
+  rightVal := right.BigInt()
+  switch operator {
+    case "+":
+      return object.NewBigInteger(leftVal.Add(leftVal, rightVal))
+    case "-":
+      return object.NewBigInteger(leftVal.Sub(leftVal, rightVal))
+    case "*":
+      return object.NewBigInteger(leftVal.Mul(leftVal, rightVal))
+    case "/":
+      if rightVal.Sign() == 0 {
+        return newError("division by zero")
+      }
+      // Quo truncates towards zero like int64 division.
+      return object.NewBigInteger(leftVal.Quo(leftVal, rightVal))
+    case "<":
+      return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) < 0)
+    case ">":
+      return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) > 0)
+    case "<=":
+      return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) <= 0)
+    case ">=":
+      return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) >= 0)
+    case "==":
+      return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) == 0)
+    case "!=":
+      return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) != 0)
+    default:
+     return newError("unknown operator: %s %s %s",
+      left.Type(), operator, right.Type())
+  }
+}
+
+func evalFloatInfixExpression(operator string, left object.Object, right object.Object) object.Object {
+  leftVal := toFloat(left)
+  rightVal := toFloat(right)
+  switch operator {
+    case "+":
+      return &object.Float{Value: leftVal + rightVal}
+    case "-":
+      return &object.Float{Value: leftVal - rightVal}
+    case "*":
+      return &object.Float{Value: leftVal * rightVal}
+    case "/":
+      return &object.Float{Value: leftVal / rightVal}
+    case "<":
+      return nativeBoolToBooleanObject(leftVal < rightVal)
+    case ">":
+      return nativeBoolToBooleanObject(leftVal > rightVal)
+    case "<=":
+      return nativeBoolToBooleanObject(leftVal <= rightVal)
+    case ">=":
+      return nativeBoolToBooleanObject(leftVal >= rightVal)
     case "==":
       return nativeBoolToBooleanObject(leftVal == rightVal)
     case "!=":
@@ -214,17 +594,33 @@ func evalIntegerInfixExpression(operator string, left object.Object, right objec
   }
 }
 
+func isNumber(obj object.Object) bool {
+  return obj.Type() == object.INTEGER_OBJ || obj.Type() == object.FLOAT_OBJ
+}
+
+// usage: integer -> float promotion, #obj must satisfy isNumber.
+func toFloat(obj object.Object) float64 {
+  if integer, ok := obj.(*object.Integer); ok {
+    if integer.IsBig() {
+      value, _ := new(big.Float).SetInt(integer.Big).Float64()
+      return value
+    }
+    return float64(integer.Value)
+  }
+  return obj.(*object.Float).Value
+}
+
 /***** If - Else expressions ******/
 
-func evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Object {
-  condition := Eval(ie.Condition, env)
-  if isError(condition) {
+func (e *Evaluator) evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Object {
+  condition := e.eval(ie.Condition, env)
+  if isSignal(condition) {
     return condition
   }
   if isTruthy(condition) {
-    return Eval(ie.Consequence, env)
+    return e.eval(ie.Consequence, env)
   } else if ie.Alternative != nil {
-    return Eval(ie.Alternative, env)
+    return e.eval(ie.Alternative, env)
   } else {
     return NULL
   }
@@ -241,33 +637,156 @@ func isTruthy(obj object.Object) bool {
     return false
   default:
     if obj.Type() == object.INTEGER_OBJ{
-      return obj.(*object.Integer).Value != 0
+      integer := obj.(*object.Integer)
+      return integer.IsBig() || integer.Value != 0
+    }
+    if obj.Type() == object.FLOAT_OBJ{
+      return obj.(*object.Float).Value != 0
     }
   }
   return false
 }
 
+/***** Logical operators *****/
+
+/* && and || evaluate their right side only when the left one doesn't -..
+* decide the result already, the result is the truthiness as a BOOLEAN. */
+func (e *Evaluator) evalLogicalExpression(node *ast.InfixExpression, env *object.Environment) object.Object {
+  left := e.eval(node.Left, env)
+  if isSignal(left) {
+    return left
+  }
+  if isTruthy(left) == (node.Operator == "||") {
+    return nativeBoolToBooleanObject(isTruthy(left))
+  }
+  right := e.eval(node.Right, env)
+  if isSignal(right) {
+    return right
+  }
+  return nativeBoolToBooleanObject(isTruthy(right))
+}
+
 /***** Functions *****/
 
-func applyFunction(fn object.Object, args []object.Object) object.Object {
-  switch fn := fn.(type) {
-    case *object.Function:
-      extendedEnv := extendFunctionEnv(fn, args)
-      evaluated := Eval(fn.Body, extendedEnv)
-      return unwrapReturnValue(evaluated)
-    case *object.Builtin:
-      return fn.Fn(args...)
-    default:
-      return newError("not a function: %s", fn.Type())
+/* the body of a function is evaluated with evalTail, a call in tail -..
+* position comes back as a tailCall and is run by the next iteration -..
+* instead of a nested Go call (see tailcall.go).
+* An error leaving the body records a Frame for the call at #site, a -..
+* tail call replaces the frame of its caller. */
+func (e *Evaluator) applyFunction(fn object.Object, args []object.Object, site *ast.CallExpression) object.Object {
+  if _, ok := fn.(*object.Function); ok {
+    if e.MaxDepth > 0 && e.depth >= e.MaxDepth {
+      return newError("maximum recursion depth exceeded (%d)", e.MaxDepth)
+    }
+    // tail calls reuse this level, so the depth doesn't grow with them.
+    e.depth++
+    defer func() { e.depth-- }()
+  }
+
+  for {
+    if err := e.tick(); err != nil {
+      return err
+    }
+    var result object.Object
+    switch fn := fn.(type) {
+      case *object.Function:
+        extendedEnv, err := e.extendFunctionEnv(fn, args)
+        if err != nil {
+          result = err
+          break
+        }
+        evaluated := e.evalTail(fn.Body, extendedEnv)
+        result = unwrapReturnValue(evaluated)
+        switch result.(type) {
+          case *object.Break, *object.Continue:
+            result = loopControlError(result)
+        }
+        if err, ok := result.(*object.Error); ok {
+          err.Frames = append(err.Frames, object.Frame{Function: fn.Name, Pos: site.Pos()})
+        }
+      case *object.Builtin:
+        e.builtinSite = site
+        result = fn.Fn(args...)
+        e.builtinSite = nil
+        result = e.trackBuiltinResult(result, args)
+      default:
+        result = newError("not a function: %s", fn.Type())
+    }
+
+    call, ok := result.(*tailCall)
+    if !ok {
+      return withPos(result, site)
+    }
+    fn, args, site = call.fn, call.args, call.node
   }
 }
 
-func extendFunctionEnv(fn *object.Function, args []object.Object) *object.Environment {
+/* binds #args to the parameters of #fn, missing optional arguments -..
+* get their default value, evaluated in the new environment so a default -..
+* can refer to the parameters before it. The parameters after it are -..
+* bound to UNINITIALIZED meanwhile, a default naming one of them is an -..
+* error rather than a lookup in the enclosing scopes. Extra arguments of -..
+* a variadic function are collected in an array bound to its rest parameter. */
+func (e *Evaluator) extendFunctionEnv(fn *object.Function, args []object.Object) (*object.Environment, *object.Error) {
+  if err := checkArity(fn, len(args)); err != nil {
+    return nil, err
+  }
   env := object.NewEnclosedEnvironment(fn.Env)
   for paramIdx, param := range fn.Parameters {
-    env.Set(param.Value, args[paramIdx])
+    if paramIdx < len(args) {
+      env.Set(param.Value, args[paramIdx])
+    } else {
+      env.Set(param.Value, UNINITIALIZED)
+    }
+  }
+  if fn.Rest != nil {
+    env.Set(fn.Rest.Value, UNINITIALIZED)
+  }
+  for paramIdx := len(args); paramIdx < len(fn.Parameters); paramIdx++ {
+    param := fn.Parameters[paramIdx]
+    value := e.eval(fn.Defaults[paramIdx], env)
+    if isError(value) {
+      return nil, value.(*object.Error)
+    }
+    env.Set(param.Value, value)
+  }
+  if fn.Rest != nil {
+    rest := []object.Object{}
+    if len(args) > len(fn.Parameters) {
+      rest = append(rest, args[len(fn.Parameters):]...)
+    }
+    restArray := e.track(&object.Array{Elements: rest})
+    if isError(restArray) {
+      return nil, restArray.(*object.Error)
+    }
+    env.Set(fn.Rest.Value, restArray)
+  }
+  return env, nil
+}
+
+func checkArity(fn *object.Function, got int) *object.Error {
+  max := len(fn.Parameters)
+  min := max
+  for i := range fn.Parameters {
+    if i < len(fn.Defaults) && fn.Defaults[i] != nil {
+      min = i
+      break
+    }
+  }
+  if got >= min && (got <= max || fn.Rest != nil) {
+    return nil
+  }
+
+  want := fmt.Sprintf("%d", max)
+  if fn.Rest != nil {
+    want = fmt.Sprintf("at least %d", min)
+  } else if min != max {
+    want = fmt.Sprintf("%d..%d", min, max)
+  }
+  if fn.Name != "" {
+    return newError("wrong number of arguments to `%s`: want=%s, got=%d", fn.Name, want, got)
   }
-  return env
+  return newError("wrong number of arguments: want=%s, got=%d", want, got)
 }
 
 func unwrapReturnValue(obj object.Object) object.Object {
@@ -280,35 +799,68 @@ func unwrapReturnValue(obj object.Object) object.Object {
 // -------
 
 
-// Usage: turn functionCall arguments into []object.Object.
-func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Object {
-  var result []object.Object
+/* Usage: turn functionCall arguments and array elements into -..
+* []object.Object, ...spread elements are expanded in place. */
+func (e *Evaluator) evalExpressions(exps []ast.Expression, env *object.Environment) []object.Object {
+  result := []object.Object{}
 
   for _, expression := range exps {
-    evaluated := Eval(expression, env)
-    if isError(evaluated) {
+    spread, isSpread := expression.(*ast.SpreadExpression)
+    if isSpread {
+      expression = spread.Value
+    }
+    evaluated := e.eval(expression, env)
+    if isSignal(evaluated) {
       return []object.Object{evaluated}
     }
-    result = append(result, evaluated)
+    if !isSpread {
+      result = append(result, evaluated)
+      continue
+    }
+    array, ok := evaluated.(*object.Array)
+    if !ok {
+      return []object.Object{withPos(newError("cannot spread %s", evaluated.Type()), spread)}
+    }
+    result = append(result, array.Elements...)
   }
   return result
 }
 
 // Usage: evaluation of if-statement body, function body.
-func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) object.Object {
+func (e *Evaluator) evalBlockStatement(block *ast.BlockStatement, env *object.Environment) object.Object {
   var result object.Object
   for _, statement := range block.Statements {
-    result = Eval(statement, env)
-    if result != nil {
-      rt := result.Type()
-      if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ {
-        return result
-      }
+    result = e.eval(statement, env)
+    if isSignal(result) {
+      return result
     }
   }
   return result
 }
 
+// objects that stop the evaluation of the enclosing blocks.
+func isSignal(obj object.Object) bool {
+  if obj == nil {
+    return false
+  }
+  switch obj.Type() {
+  case object.RETURN_VALUE_OBJ, object.ERROR_OBJ, object.BREAK_OBJ, object.CONTINUE_OBJ:
+    return true
+  }
+  return false
+}
+
+// big integers saturate to the int64 range, handy for indexes and bounds.
+func int64Value(integer *object.Integer) int64 {
+  if !integer.IsBig() {
+    return integer.Value
+  }
+  if integer.Big.Sign() < 0 {
+    return math.MinInt64
+  }
+  return math.MaxInt64
+}
+
 func nativeBoolToBooleanObject(input bool) *object.Boolean {
   if input {
     return TRUE
@@ -318,6 +870,9 @@ func nativeBoolToBooleanObject(input bool) *object.Boolean {
 
 func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object {
   if val, ok := env.Get(node.Value); ok {
+    if val == UNINITIALIZED {
+      return withPos(newError("parameter %s is used before its default value is set", node.Value), node)
+    }
     return val 
   }
 
@@ -325,13 +880,21 @@ func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object
     return builtin
   }
 
-  return newError("identifier not found: " + node.Value)
+  return withPos(newError("identifier not found: " + node.Value), node)
 }
 
 /****** Errors ******/
 
 func newError(format string, a ...interface{}) *object.Error {
-  return &object.Error{Message: fmt.Sprintf(format, a...)}
+  return &object.Error{Message: fmt.Sprintf(format, a...), Kind: object.RUNTIME_ERROR}
+}
+
+// stamps the position of #node on #obj when it is an error that has none yet.
+func withPos(obj object.Object, node ast.Node) object.Object {
+  if err, ok := obj.(*object.Error); ok && !err.Pos.IsValid() {
+    err.Pos = node.Pos()
+  }
+  return obj
 }
 
 func isError(obj object.Object) bool {
@@ -1,10 +1,14 @@
 package evaluator
 
 import (
+  "context"
+  "Monkey/ast"
   "Monkey/lexer"
   "Monkey/object"
   "Monkey/parser"
+  "Monkey/token"
   "testing"
+  "time"
   )
 
 
@@ -78,6 +82,95 @@ func testIntegerObject(t *testing.T, obj object.Object, expected int64) bool {
   return true
 }
 
+/***** Big integers ******/
+
+func TestBigIntegerPromotion(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected string
+  }{
+    {"9223372036854775807 + 1", "9223372036854775808"},
+    {"-9223372036854775807 - 2", "-9223372036854775809"},
+    {"-9223372036854775808", "-9223372036854775808"},
+    {"-(-9223372036854775807 - 1)", "9223372036854775808"},
+    {"4294967296 * 4294967296", "18446744073709551616"},
+    {"-1 * (-9223372036854775807 - 1)", "9223372036854775808"},
+    {"(-9223372036854775807 - 1) / -1", "9223372036854775808"},
+    {"123456789012345678901234567890", "123456789012345678901234567890"},
+    {"123456789012345678901234567890 * 10 / 10", "123456789012345678901234567890"},
+    {"let f = fn(n) { if (n < 2) { 1 } else { n * f(n - 1) } }; f(25)", "15511210043330985984000000"},
+    {`int("99999999999999999999")`, "99999999999999999999"},
+    {"floor(1e20)", "100000000000000000000"},
+    {"9223372036854775808 / 2.0", "4.611686018427388e+18"},
+  }
+  for _, tt := range tests {
+    evaluated := testEval(tt.input)
+    if _, ok := evaluated.(*object.Error); ok {
+      t.Errorf("%s: unexpected error %s", tt.input, evaluated.Inspect())
+      continue
+    }
+    if evaluated.Inspect() != tt.expected {
+      t.Errorf("%s wrong. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+    }
+  }
+}
+
+func TestBigIntegerDemotion(t *testing.T) {
+  evaluated := testEval("9223372036854775807 + 10 - 10")
+  if integer, ok := evaluated.(*object.Integer); !ok || integer.IsBig() {
+    t.Fatalf("result was not demoted to int64. got=%+v", evaluated)
+  }
+  testIntegerObject(t, evaluated, 9223372036854775807)
+
+  tests := []struct {
+    input    string
+    expected bool
+  }{
+    {"9223372036854775808 > 9223372036854775807", true},
+    {"9223372036854775808 == 9223372036854775807 + 1", true},
+    {"-9223372036854775809 < -9223372036854775808", true},
+    {`{9223372036854775808: true}[9223372036854775807 + 1]`, true},
+    {`has({9223372036854775808: 1}, -9223372036854775808)`, false},
+  }
+  for _, tt := range tests {
+    testBooleanObject(t, testEval(tt.input), tt.expected)
+  }
+}
+
+/***** Float Expressions ******/
+
+func TestEvalFloatExpression(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected float64
+  }{
+    {"3.5", 3.5},
+    {"-2.25", -2.25},
+    {"1e3", 1000},
+    {"0.1 + 0.2", 0.30000000000000004},
+    {"1 + 0.5", 1.5},
+    {"0.5 * 4", 2},
+    {"7 / 2.0", 3.5},
+    {"10 - 2.5 * 2", 5},
+  }
+  for _, tt := range tests {
+    testFloatObject(t, testEval(tt.input), tt.expected)
+  }
+}
+
+func testFloatObject(t *testing.T, obj object.Object, expected float64) bool {
+  result, ok := obj.(*object.Float)
+  if !ok {
+    t.Errorf("object is not Float. got=%T (%+v)", obj, obj)
+    return false
+  }
+  if result.Value != expected {
+    t.Errorf("object has wrong value. got=%g, want=%g", result.Value, expected)
+    return false
+  }
+  return true
+}
+
 /***** Boolean Expressions ******/
 
 func TestEvalBooleanExpression(t *testing.T) {
@@ -104,6 +197,25 @@ func TestEvalBooleanExpression(t *testing.T) {
     {"(1 < 2) == false", false},
     {"(1 > 2) == true", false},
     {"(1 > 2) == false", true},
+    {"1.5 < 2", true},
+    {"2 > 2.5", false},
+    {"1.0 == 1", true},
+    {"0.1 + 0.2 != 0.3", true},
+    {"!0.0", true},
+    {"1 <= 2", true},
+    {"2 <= 2", true},
+    {"3 <= 2", false},
+    {"1 >= 2", false},
+    {"2 >= 2", true},
+    {"2.5 >= 2", true},
+    {"9223372036854775808 >= 9223372036854775807", true},
+    {"true && true", true},
+    {"true && false", false},
+    {"false || true", true},
+    {"false || false", false},
+    {"1 && 2.5", true},
+    {"0 || false", false},
+    {"let a = 5; a >= 0 && a < 10", true},
   }
 
   for _,test := range tests {
@@ -199,6 +311,39 @@ func TestBangOperator(t *testing.T) {
     testBooleanObject(t, evaluated, tt.expected)
   }
 }
+// the right side of && and || is only evaluated when it decides the result.
+func TestLogicalShortCircuit(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected interface{}
+  }{
+    {"false && undefinedName", false},
+    {"true || undefinedName", true},
+    {"true && undefinedName", "identifier not found: undefinedName"},
+    {"let n = 0; let inc = fn() { n += 1; true }; false && inc(); true || inc(); n", 0},
+    {"let n = 0; let inc = fn() { n += 1; true }; true && inc(); false || inc(); n", 2},
+    {"let a = []; len(a) > 0 && a[0] > 1", false},
+  }
+  for _, tt := range tests {
+    evaluated := testEval(tt.input)
+    switch expected := tt.expected.(type) {
+    case bool:
+      testBooleanObject(t, evaluated, expected)
+    case int:
+      testIntegerObject(t, evaluated, int64(expected))
+    case string:
+      errObj, ok := evaluated.(*object.Error)
+      if !ok {
+        t.Errorf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+        continue
+      }
+      if errObj.Message != expected {
+        t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+      }
+    }
+  }
+}
+
 /***** Strings tests ******/
 
 func TestStringLiteral(t *testing.T) {
@@ -225,6 +370,76 @@ func TestStringConcatenation(t *testing.T) {
   }
 }
 
+// len, indexing and slice count characters, bytes() exposes the UTF-8 encoding.
+func TestUnicodeStrings(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected interface{}
+  }{
+    {`len("héllo")`, 5},
+    {`len("😀")`, 1},
+    {`"héllo"[1]`, "é"},
+    {`"héllo"[-1]`, "o"},
+    {`"日本語"[2]`, "語"},
+    {`"héllo"[5]`, nil},
+    {`"héllo"[-6]`, nil},
+    {`slice("héllo", 1, 3)`, "él"},
+    {`slice("日本語", -2)`, "本語"},
+    {`slice("abc", 2, 1)`, ""},
+    {`bytes("é")`, []int64{0xc3, 0xa9}},
+    {`bytes("")`, []int64{}},
+    {`len(bytes("😀"))`, 4},
+    {`let größe = 3; größe * 2`, 6},
+  }
+  for _, tt := range tests {
+    evaluated := testEval(tt.input)
+    switch expected := tt.expected.(type) {
+    case int:
+      testIntegerObject(t, evaluated, int64(expected))
+    case nil:
+      testNullObject(t, evaluated)
+    case string:
+      str, ok := evaluated.(*object.String)
+      if !ok {
+        t.Errorf("object is not String. got=%T (%+v)", evaluated, evaluated)
+        continue
+      }
+      if str.Value != expected {
+        t.Errorf("String has wrong value. expected=%q, got=%q", expected, str.Value)
+      }
+    case []int64:
+      array, ok := evaluated.(*object.Array)
+      if !ok {
+        t.Errorf("obj not Array. got=%T (%+v)", evaluated, evaluated)
+        continue
+      }
+      if len(array.Elements) != len(expected) {
+        t.Errorf("wrong num of elements. want=%d, got=%d", len(expected), len(array.Elements))
+        continue
+      }
+      for i, expectedElem := range expected {
+        testIntegerObject(t, array.Elements[i], expectedElem)
+      }
+    }
+  }
+
+  errors := map[string]string{
+    `bytes(1)`:         "argument to `bytes` must be STRING, got INTEGER",
+    `slice(1, 0)`:      "argument to `slice` must be ARRAY or STRING, got INTEGER",
+    `"abc"["a"]`:       "index operator not supported: STRING[STRING]",
+  }
+  for input, expected := range errors {
+    errObj, ok := testEval(input).(*object.Error)
+    if !ok {
+      t.Errorf("%s: object is not Error", input)
+      continue
+    }
+    if errObj.Message != expected {
+      t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+    }
+  }
+}
+
 /***** Functions tests ******/
 
 func TestFunctionObject(t *testing.T) {
@@ -264,6 +479,293 @@ func TestFunctionApplication(t *testing.T) {
   }
 }
 
+func TestFunctionArity(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected interface{}
+  }{
+    {"let add = fn(a, b) { a + b }; add(1)", "wrong number of arguments to `add`: want=2, got=1"},
+    {"let add = fn(a, b) { a + b }; add(1, 2, 3)", "wrong number of arguments to `add`: want=2, got=3"},
+    {"fn(a, b) { a }(1)", "wrong number of arguments: want=2, got=1"},
+    {"fn() { 1 }(1)", "wrong number of arguments: want=0, got=1"},
+    {"let f = fn(a, b = 10) { a + b }; f(1)", 11},
+    {"let f = fn(a, b = 10) { a + b }; f(1, 2)", 3},
+    {"let f = fn(a, b = a * 2) { a + b }; f(3)", 9},
+    {"let f = fn(a, b = 10) { a + b }; f()", "wrong number of arguments to `f`: want=1..2, got=0"},
+    {"let f = fn(a = 1, b = 2) { a + b }; f()", 3},
+    {"let f = fn(a = missing) { a }; f()", "identifier not found: missing"},
+    {"let b = 5; let f = fn(a = b, b = 1) { a }; f()", "parameter b is used before its default value is set"},
+    {"let b = 5; let f = fn(a = b, b = 1) { a }; f(2)", 2},
+    {"let f = fn(a = a) { a }; f()", "parameter a is used before its default value is set"},
+    {"let f = fn(a = len(rest), ...rest) { a }; f()", "parameter rest is used before its default value is set"},
+    {"let f = fn(g = fn() { b }, b = 1) { g() }; f()", 1},
+  }
+  for _, tt := range tests {
+    evaluated := testEval(tt.input)
+    switch expected := tt.expected.(type) {
+    case int:
+      testIntegerObject(t, evaluated, int64(expected))
+    case string:
+      errObj, ok := evaluated.(*object.Error)
+      if !ok {
+        t.Errorf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+        continue
+      }
+      if errObj.Message != expected {
+        t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+      }
+    }
+  }
+}
+
+func TestVariadicFunctions(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected string
+  }{
+    {"let f = fn(first, ...rest) { rest }; f(1, 2, 3)", "[2, 3]"},
+    {"let f = fn(first, ...rest) { rest }; f(1)", "[]"},
+    {"let f = fn(...all) { len(all) }; f()", "0"},
+    {"let f = fn(a, b = 5, ...rest) { [a, b, rest] }; f(1)", "[1, 5, []]"},
+    {"let f = fn(a, b = 5, ...rest) { [a, b, rest] }; f(1, 2, 3, 4)", "[1, 2, [3, 4]]"},
+    {"let f = fn(first, ...rest) { rest }; f()", "ERROR: 1:39: wrong number of arguments to `f`: want=at least 1, got=0"},
+    {"let add = fn(a, b) { a + b }; let xs = [1, 2]; add(...xs)", "3"},
+    {"let add = fn(a, b) { a + b }; add(...[1], 2)", "3"},
+    {"let add = fn(a, b) { a + b }; add(...[1, 2, 3])", "ERROR: 1:34: wrong number of arguments to `add`: want=2, got=3"},
+    {"let f = fn(...all) { all }; f(0, ...[1, 2], ...[], 3)", "[0, 1, 2, 3]"},
+    {"[0, ...[1, 2], 3]", "[0, 1, 2, 3]"},
+    {"len(...[[1, 2]])", "2"},
+    {"let f = fn(...all) { all }; f(...1)", "ERROR: 1:31: cannot spread INTEGER"},
+  }
+  for _, tt := range tests {
+    evaluated := testEval(tt.input)
+    if evaluated.Inspect() != tt.expected {
+      t.Errorf("%s wrong. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+    }
+  }
+}
+
+/***** Tail calls tests *****/
+
+func TestTailCalls(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected interface{}
+  }{
+    {`let loop = fn(n, acc) { if (n == 0) { acc } else { loop(n - 1, acc + n) } };
+      loop(1000000, 0)`, 500000500000},
+    {`let loop = fn(n) { if (n == 0) { return 0; } return loop(n - 1); };
+      loop(1000000)`, 0},
+    {`let even = fn(n) { if (n == 0) { true } else { odd(n - 1) } };
+      let odd = fn(n) { if (n == 0) { false } else { even(n - 1) } };
+      even(100001)`, false},
+    {`let count = fn(n) { if (n == 0) { 0 } else { 1 + count(n - 1) } };
+      count(100)`, 100},
+    {`let f = fn(n) { if (n > 0) { return f(n - 1) + 1; } 0 }; f(50)`, 50},
+    {`let g = fn(x) { len(x) }; g("four")`, 4},
+    {`let loop = fn(n) { if (n == 0) { missing } else { loop(n - 1) } }; loop(5)`,
+      "identifier not found: missing"},
+    {`let loop = fn(n) { if (n == 0) { loop() } else { loop(n - 1) } }; loop(5)`,
+      "wrong number of arguments to `loop`: want=1, got=0"},
+  }
+  for _, tt := range tests {
+    evaluated := testEval(tt.input)
+    switch expected := tt.expected.(type) {
+    case int:
+      testIntegerObject(t, evaluated, int64(expected))
+    case bool:
+      testBooleanObject(t, evaluated, expected)
+    case string:
+      errObj, ok := evaluated.(*object.Error)
+      if !ok {
+        t.Errorf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+        continue
+      }
+      if errObj.Message != expected {
+        t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+      }
+    }
+  }
+}
+
+/***** Recursion depth tests *****/
+
+func TestRecursionDepthLimit(t *testing.T) {
+  count := "let count = fn(n) { if (n == 0) { 0 } else { 1 + count(n - 1) } };"
+
+  testIntegerObject(t, testEval(count+"count(9000)"), 9000)
+
+  evaluated := testEval(count + "count(1000000)")
+  errObj, ok := evaluated.(*object.Error)
+  if !ok {
+    t.Fatalf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+  }
+  if errObj.Message != "maximum recursion depth exceeded (10000)" {
+    t.Errorf("wrong error message. got=%q", errObj.Message)
+  }
+
+  e := New()
+  e.MaxDepth = 10
+  program := parser.New(lexer.New(count + "count(10)")).ParseProgram()
+  evaluated = e.Eval(program, object.NewEnvironment())
+  if errObj, ok := evaluated.(*object.Error); !ok || errObj.Message != "maximum recursion depth exceeded (10)" {
+    t.Errorf("MaxDepth not enforced. got=%+v", evaluated)
+  }
+
+  // the depth is released once calls return, and tail calls don't count.
+  program = parser.New(lexer.New(count + "count(9); count(9); let loop = fn(n) { if (n > 0) { loop(n - 1) } else { n } }; loop(100)")).ParseProgram()
+  testIntegerObject(t, e.Eval(program, object.NewEnvironment()), 0)
+}
+
+func TestExecutionBudget(t *testing.T) {
+  forever := "let forever = fn(n) { forever(n + 1) }; forever(0);"
+  program := parser.New(lexer.New(forever)).ParseProgram()
+
+  e := New()
+  e.MaxSteps = 1000
+  testBudgetError(t, e.Eval(program, object.NewEnvironment()), "step limit exceeded (1000)")
+
+  e = New()
+  e.Deadline = time.Now().Add(10 * time.Millisecond)
+  testBudgetError(t, e.Eval(program, object.NewEnvironment()), "deadline exceeded")
+
+  ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+  defer cancel()
+  testBudgetError(t, New().EvalContext(ctx, program, object.NewEnvironment()), "deadline exceeded")
+
+  ctx, cancel = context.WithCancel(context.Background())
+  cancel()
+  testBudgetError(t, New().EvalContext(ctx, program, object.NewEnvironment()), "evaluation canceled")
+
+  // a program within budget runs to completion.
+  e = New()
+  e.MaxSteps = 1000
+  program = parser.New(lexer.New("let add = fn(a, b) { a + b }; add(1, add(2, 3))")).ParseProgram()
+  testIntegerObject(t, e.Eval(program, object.NewEnvironment()), 6)
+}
+
+func testBudgetError(t *testing.T, obj object.Object, expected string) {
+  t.Helper()
+  errObj, ok := obj.(*object.Error)
+  if !ok {
+    t.Fatalf("object is not Error. got=%T (%+v)", obj, obj)
+  }
+  if errObj.Kind != object.BUDGET_ERROR {
+    t.Errorf("wrong error kind. got=%q", errObj.Kind)
+  }
+  if errObj.Message != expected {
+    t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+  }
+}
+
+func TestMemoryLimit(t *testing.T) {
+  tests := []string{
+    `let grow = fn(s) { grow(s + s) }; grow("x")`,
+    `let grow = fn(a) { grow(concat(a, a)) }; grow([1])`,
+    `let grow = fn(n) { grow(n * n) }; grow(2)`,
+    // the integers built by bytes() count, not only the array holding them.
+    `let s = "x"; let i = 0; while (i < 15) { s = s + s; i += 1 }; bytes(s)`,
+  }
+
+  for _, tt := range tests {
+    e := New()
+    e.MaxMemory = 1 << 20
+    program := parser.New(lexer.New(tt)).ParseProgram()
+    evaluated := e.Eval(program, object.NewEnvironment())
+    errObj, ok := evaluated.(*object.Error)
+    if !ok {
+      t.Errorf("object is not Error for %q. got=%T (%+v)", tt, evaluated, evaluated)
+      continue
+    }
+    if errObj.Kind != object.MEMORY_ERROR || errObj.Message != "memory limit exceeded (1048576 bytes)" {
+      t.Errorf("wrong error for %q. got=%q (%s)", tt, errObj.Message, errObj.Kind)
+    }
+    if e.Allocated() > 2 << 20 + 64 {
+      t.Errorf("allocations of %q overshot the limit. got=%d", tt, e.Allocated())
+    }
+  }
+
+  // a builtin returning a value its argument holds doesn't allocate.
+  e := New()
+  e.MaxMemory = 1 << 20
+  env := object.NewEnvironment()
+  e.Eval(parser.New(lexer.New(`let s = "x"; let i = 0; while (i < 14) { s = s + s; i += 1 }; let a = [s];`)).ParseProgram(), env)
+  before := e.Allocated()
+  for i := 0; i < 10; i++ {
+    e.Eval(parser.New(lexer.New(`first(a); last(a); a[0]`)).ParseProgram(), env)
+  }
+  if e.Allocated() != before {
+    t.Errorf("first/last charged their result again. before=%d, after=%d", before, e.Allocated())
+  }
+
+  // literals and fixed-size numbers aren't charged, loops over them don't run out.
+  scalars := []string{
+    `let i = 0; while (i < 70000) { i += 1 }; i`,
+    `let i = 0; while (i < 70000) { let s = "x"; let f = 1.5 * 2; i = i + 1 }; i`,
+    `let n = 0; for (x in 0..<70000) { n += x }; n`,
+  }
+  for _, tt := range scalars {
+    e := New()
+    e.MaxMemory = 1 << 20
+    program := parser.New(lexer.New(tt)).ParseProgram()
+    if evaluated := e.Eval(program, object.NewEnvironment()); isError(evaluated) {
+      t.Errorf("%q failed. got=%s", tt, evaluated.Inspect())
+    }
+    if e.Allocated() != 0 {
+      t.Errorf("%q charged scalars. got=%d", tt, e.Allocated())
+    }
+  }
+
+  // accounting is opt-in.
+  e = New()
+  program := parser.New(lexer.New(`let s = "abc" + "def"; [s, s]`)).ParseProgram()
+  e.Eval(program, object.NewEnvironment())
+  if e.Allocated() != 0 {
+    t.Errorf("allocations accounted without MaxMemory. got=%d", e.Allocated())
+  }
+}
+
+func TestErrorFrames(t *testing.T) {
+  input := `let inner = fn(a) { a + "x" };
+let outer = fn(x) { let y = inner(x); y };
+let tail = fn(x) { outer(x) };
+tail(1);`
+
+  evaluated := testEval(input)
+  errObj, ok := evaluated.(*object.Error)
+  if !ok {
+    t.Fatalf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+  }
+  // innermost call first, the tail call to outer replaced the frame of tail.
+  expected := []struct {
+    function string
+    pos      string
+  }{
+    {"inner", "2:34"},
+    {"outer", "3:25"},
+  }
+  if len(errObj.Frames) != len(expected) {
+    t.Fatalf("wrong number of frames. want=%d, got=%d (%+v)", len(expected), len(errObj.Frames), errObj.Frames)
+  }
+  for i, frame := range errObj.Frames {
+    if frame.Function != expected[i].function || frame.Pos.String() != expected[i].pos {
+      t.Errorf("frames[%d] wrong. want=%s at %s, got=%s at %s", i,
+        expected[i].function, expected[i].pos, frame.Function, frame.Pos)
+    }
+  }
+}
+
+func TestArityErrorPosition(t *testing.T) {
+  evaluated := testEval("let add = fn(a, b) { a + b };\nlet x = add(1);")
+  errObj, ok := evaluated.(*object.Error)
+  if !ok {
+    t.Fatalf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+  }
+  if errObj.Pos.String() != "2:12" {
+    t.Errorf("wrong error position. got=%q", errObj.Pos.String())
+  }
+}
+
 /***** Errors handling tests *****/
 
 func TestErrorHandling(t *testing.T) {
@@ -314,6 +816,30 @@ func TestErrorHandling(t *testing.T) {
     `"Hello" - "World"`,
     "unknown operator: STRING - STRING",
     },
+    {
+    `{"name": "Monkey"}[fn(x) { x }];`,
+    "unusable as hash key: FUNCTION",
+    },
+    {
+    `{fn(x) { x }: 1};`,
+    "unusable as hash key: FUNCTION",
+    },
+    {
+    `has({}, [1]);`,
+    "unusable as hash key: ARRAY",
+    },
+    {
+    "1 / 0",
+    "division by zero",
+    },
+    {
+    "let x = 0; 10 / (x * 2)",
+    "division by zero",
+    },
+    {
+    "123456789012345678901234567890 / 0",
+    "division by zero",
+    },
   }
 
   for _, tt := range tests {
@@ -331,6 +857,74 @@ func TestErrorHandling(t *testing.T) {
   }
 }
 
+func TestErrorPositions(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected string
+  }{
+    {"foobar", "1:1"},
+    {"let a = 1;\nlet b = a + true;", "2:11"},
+    {"let f = fn(x) {\n  -x\n};\nf(true);", "2:3"},
+    {`len(1)`, "1:4"},
+  }
+  for _, tt := range tests {
+    errObj, ok := testEval(tt.input).(*object.Error)
+    if !ok {
+      t.Errorf("no error object returned for %q", tt.input)
+      continue
+    }
+    if errObj.Pos.String() != tt.expected {
+      t.Errorf("wrong error position. expected=%q, got=%q",
+      tt.expected, errObj.Pos.String())
+    }
+  }
+}
+
+func TestPanicsBecomeErrors(t *testing.T) {
+  builtins["boom"] = &object.Builtin{
+    Fn: func(args ...object.Object) object.Object {
+      panic("kaboom")
+    },
+  }
+  defer delete(builtins, "boom")
+
+  env := object.NewEnvironment()
+  evaluated := Eval(parser.New(lexer.New("let a = 1;\nboom()")).ParseProgram(), env)
+  errObj, ok := evaluated.(*object.Error)
+  if !ok {
+    t.Fatalf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+  }
+  if errObj.Message != "internal error: kaboom" {
+    t.Errorf("wrong error message. got=%q", errObj.Message)
+  }
+  if errObj.Pos.String() != "2:5" {
+    t.Errorf("wrong error position. got=%q", errObj.Pos.String())
+  }
+  if errObj.Kind != object.INTERNAL_ERROR {
+    t.Errorf("wrong error kind. got=%q", errObj.Kind)
+  }
+
+  // the environment survives the panic.
+  testIntegerObject(t, Eval(parser.New(lexer.New("a")).ParseProgram(), env), 1)
+
+  // panics of the evaluator itself are positioned at the innermost node.
+  env.Set("broken", (*object.Integer)(nil))
+  evaluated = Eval(parser.New(lexer.New("let b = 1;\nlet c = [b, -broken];")).ParseProgram(), env)
+  errObj, ok = evaluated.(*object.Error)
+  if !ok || errObj.Kind != object.INTERNAL_ERROR {
+    t.Fatalf("no internal error returned. got=%T(%+v)", evaluated, evaluated)
+  }
+  if errObj.Pos.String() != "2:13" {
+    t.Errorf("wrong error position. got=%q", errObj.Pos.String())
+  }
+
+  // try can't catch a panic.
+  evaluated = Eval(parser.New(lexer.New(`try { boom() } catch (e) { "caught" }`)).ParseProgram(), env)
+  if errObj, ok := evaluated.(*object.Error); !ok || errObj.Kind != object.INTERNAL_ERROR {
+    t.Errorf("internal error was caught. got=%T(%+v)", evaluated, evaluated)
+  }
+}
+
 /** builtin functions tests **/
 
 func TestBuiltinFunctions(t *testing.T) {
@@ -362,3 +956,498 @@ func TestBuiltinFunctions(t *testing.T) {
     }
   }
 }
+
+/***** Arrays tests *****/
+
+func TestArrayLiterals(t *testing.T) {
+  input := "[1, 2 * 2, 3 + 3]"
+  evaluated := testEval(input)
+  result, ok := evaluated.(*object.Array)
+  if !ok {
+    t.Fatalf("object is not Array. got=%T (%+v)", evaluated, evaluated)
+  }
+  if len(result.Elements) != 3 {
+    t.Fatalf("array has wrong num of elements. got=%d", len(result.Elements))
+  }
+  testIntegerObject(t, result.Elements[0], 1)
+  testIntegerObject(t, result.Elements[1], 4)
+  testIntegerObject(t, result.Elements[2], 6)
+}
+
+func TestArrayIndexExpressions(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected interface{}
+  }{
+    {"[1, 2, 3][0]", 1},
+    {"[1, 2, 3][1]", 2},
+    {"[1, 2, 3][2]", 3},
+    {"let i = 0; [1][i];", 1},
+    {"[1, 2, 3][1 + 1];", 3},
+    {"let myArray = [1, 2, 3]; myArray[2];", 3},
+    {"let myArray = [1, 2, 3]; myArray[0] + myArray[1] + myArray[2];", 6},
+    {"let myArray = [1, 2, 3]; let i = myArray[0]; myArray[i]", 2},
+    {"[1, 2, 3][3]", nil},
+    {"[1, 2, 3][-1]", 3},
+    {"[1, 2, 3][-3]", 1},
+    {"[1, 2, 3][-4]", nil},
+  }
+  for _, tt := range tests {
+    evaluated := testEval(tt.input)
+    integer, ok := tt.expected.(int)
+    if ok {
+      testIntegerObject(t, evaluated, int64(integer))
+    } else {
+      testNullObject(t, evaluated)
+    }
+  }
+}
+
+func TestArrayBuiltins(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected interface{}
+  }{
+    {`len([1, 2, 3])`, 3},
+    {`len([])`, 0},
+    {`first([1, 2, 3])`, 1},
+    {`first([])`, nil},
+    {`first(1)`, "argument to `first` must be ARRAY, got INTEGER"},
+    {`last([1, 2, 3])`, 3},
+    {`last([])`, nil},
+    {`rest([1, 2, 3])`, []int64{2, 3}},
+    {`rest([])`, nil},
+    {`push([], 1)`, []int64{1}},
+    {`let a = [1]; push(a, 2); a`, []int64{1}},
+    {`slice([1, 2, 3, 4], 1)`, []int64{2, 3, 4}},
+    {`slice([1, 2, 3, 4], 1, 3)`, []int64{2, 3}},
+    {`slice([1, 2, 3, 4], -2)`, []int64{3, 4}},
+    {`slice([1, 2, 3, 4], 3, 1)`, []int64{}},
+    {`slice([1, 2, 3, 4], 0, 10)`, []int64{1, 2, 3, 4}},
+    {`slice([1], "a")`, "bounds of `slice` must be INTEGER, got STRING"},
+    {`concat([1], [], [2, 3])`, []int64{1, 2, 3}},
+    {`concat([1], 2)`, "arguments to `concat` must be ARRAY, got INTEGER"},
+  }
+  for _, tt := range tests {
+    evaluated := testEval(tt.input)
+    switch expected := tt.expected.(type) {
+    case int:
+      testIntegerObject(t, evaluated, int64(expected))
+    case nil:
+      testNullObject(t, evaluated)
+    case string:
+      errObj, ok := evaluated.(*object.Error)
+      if !ok {
+        t.Errorf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+        continue
+      }
+      if errObj.Message != expected {
+        t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+      }
+    case []int64:
+      array, ok := evaluated.(*object.Array)
+      if !ok {
+        t.Errorf("obj not Array. got=%T (%+v)", evaluated, evaluated)
+        continue
+      }
+      if len(array.Elements) != len(expected) {
+        t.Errorf("wrong num of elements. want=%d, got=%d", len(expected), len(array.Elements))
+        continue
+      }
+      for i, expectedElem := range expected {
+        testIntegerObject(t, array.Elements[i], expectedElem)
+      }
+    }
+  }
+}
+
+/***** Hashes tests *****/
+
+func TestHashLiterals(t *testing.T) {
+  input := `let two = "two";
+  {
+    "one": 10 - 9,
+    two: 1 + 1,
+    "thr" + "ee": 6 / 2,
+    4: 4,
+    true: 5,
+    false: 6
+  }`
+  evaluated := testEval(input)
+  result, ok := evaluated.(*object.Hash)
+  if !ok {
+    t.Fatalf("Eval didn't return Hash. got=%T (%+v)", evaluated, evaluated)
+  }
+  expected := map[object.HashKey]int64{
+    (&object.String{Value: "one"}).HashKey():   1,
+    (&object.String{Value: "two"}).HashKey():   2,
+    (&object.String{Value: "three"}).HashKey(): 3,
+    (&object.Integer{Value: 4}).HashKey():      4,
+    TRUE.HashKey():                            5,
+    FALSE.HashKey():                           6,
+  }
+  if len(result.Pairs) != len(expected) {
+    t.Fatalf("Hash has wrong num of pairs. got=%d", len(result.Pairs))
+  }
+  for expectedKey, expectedValue := range expected {
+    pair, ok := result.Pairs[expectedKey]
+    if !ok {
+      t.Errorf("no pair for given key in Pairs")
+    }
+    testIntegerObject(t, pair.Value, expectedValue)
+  }
+}
+
+func TestHashIndexExpressions(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected interface{}
+  }{
+    {`{"foo": 5}["foo"]`, 5},
+    {`{"foo": 5}["bar"]`, nil},
+    {`let key = "foo"; {"foo": 5}[key]`, 5},
+    {`{}["foo"]`, nil},
+    {`{5: 5}[5]`, 5},
+    {`{true: 5}[true]`, 5},
+    {`{false: 5}[false]`, 5},
+  }
+  for _, tt := range tests {
+    evaluated := testEval(tt.input)
+    integer, ok := tt.expected.(int)
+    if ok {
+      testIntegerObject(t, evaluated, int64(integer))
+    } else {
+      testNullObject(t, evaluated)
+    }
+  }
+}
+
+func TestHashBuiltins(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected string
+  }{
+    {`keys({"a": 1, "b": 2})`, "[a, b]"},
+    {`values({"a": 1, "b": 2})`, "[1, 2]"},
+    {`len({"a": 1, "b": 2})`, "2"},
+    {`has({"a": 1}, "a")`, "true"},
+    {`has({"a": 1}, "b")`, "false"},
+    {`delete({"a": 1, "b": 2}, "a")`, "{b: 2}"},
+    {`let h = {"a": 1}; delete(h, "a"); h`, "{a: 1}"},
+    {`merge({"a": 1, "b": 2}, {"b": 3, "c": 4})`, "{a: 1, b: 3, c: 4}"},
+    {`keys([])`, "ERROR: 1:5: argument to `keys` must be HASH, got ARRAY"},
+  }
+  for _, tt := range tests {
+    evaluated := testEval(tt.input)
+    if evaluated.Inspect() != tt.expected {
+      t.Errorf("%s wrong. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+    }
+  }
+}
+
+/***** Numeric conversions tests *****/
+
+func TestNumericBuiltins(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected interface{}
+  }{
+    {`int(3.9)`, 3},
+    {`int(-3.9)`, -3},
+    {`int("42")`, 42},
+    {`int(7)`, 7},
+    {`int("x")`, "could not convert \"x\" to INTEGER"},
+    {`int("010")`, 10},
+    {`int("08")`, 8},
+    {`int(" -7 ")`, -7},
+    {`int("0x1F")`, "could not convert \"0x1F\" to INTEGER"},
+    {`int("1_000")`, "could not convert \"1_000\" to INTEGER"},
+    {`float(2)`, 2.0},
+    {`float("2.5")`, 2.5},
+    {`round(2.5)`, 3},
+    {`round(-2.4)`, -2},
+    {`floor(2.7)`, 2},
+    {`floor(-2.1)`, -3},
+    {`ceil(2.1)`, 3},
+    {`ceil(5)`, 5},
+    {`floor(1.0 / 0)`, "`floor` cannot convert +Inf to INTEGER"},
+    {`ceil("a")`, "argument to `ceil` must be INTEGER or FLOAT, got STRING"},
+  }
+  for _, tt := range tests {
+    evaluated := testEval(tt.input)
+    switch expected := tt.expected.(type) {
+    case int:
+      testIntegerObject(t, evaluated, int64(expected))
+    case float64:
+      testFloatObject(t, evaluated, expected)
+    case string:
+      errObj, ok := evaluated.(*object.Error)
+      if !ok {
+        t.Errorf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+        continue
+      }
+      if errObj.Message != expected {
+        t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+      }
+    }
+  }
+}
+
+func TestScriptArgs(t *testing.T) {
+  SetArgs([]string{"script.monkey", "first"})
+  defer SetArgs([]string{})
+
+  testIntegerObject(t, testEval(`argc()`), 2)
+  str, ok := testEval(`argv(1)`).(*object.String)
+  if !ok || str.Value != "first" {
+    t.Errorf("argv(1) wrong. got=%+v", str)
+  }
+  testNullObject(t, testEval(`argv(2)`))
+  if args := testEval(`args()`).Inspect(); args != "[script.monkey, first]" {
+    t.Errorf("args() wrong. got=%s", args)
+  }
+}
+
+func TestTryCatch(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected string  // Inspect() of the result
+  }{
+    {`try { 1 } catch (e) { 2 }`, "1"},
+    {`try { throw "bad"; 1 } catch (e) { e["message"] }`, "bad"},
+    {`try { throw 42 } catch (e) { [e["kind"], e["value"]] }`, "[Error, 42]"},
+    {`try { 5 + true } catch (e) { e["message"] }`, "type mismatch: INTEGER + BOOLEAN"},
+    {`try { foobar } catch (e) { [e["kind"], e["line"], e["column"], e["value"]] }`, "[RuntimeError, 1, 7, null]"},
+    {`try { throw {"message": "custom", "code": 7} } catch (e) { [e["message"], e["value"]["code"]] }`, "[custom, 7]"},
+    {`let f = fn(x) { if (x > 2) { throw "too big" } x }; try { f(1) + f(3) } catch (e) { e["message"] }`, "too big"},
+    {`try { throw "a" } catch { 3 }`, "3"},
+    {`let x = 0; try { let x = 1 } finally { x }`, "null"},
+    {`let f = fn() { try { return 1 } finally { puts } ; 2 }; f()`, "1"},
+    {`let f = fn() { try { return 1 } finally { return 2 } }; f()`, "2"},
+    {`try { 1 } finally { 5 + true }`, "ERROR: 1:23: type mismatch: INTEGER + BOOLEAN"},
+    {`try { throw "inner" } catch (e) { throw e }`, "ERROR: 1:35: inner"},
+    {`try { try { throw "a" } finally { 1 } } catch (e) { e["message"] }`, "a"},
+    {`throw "uncaught"; 1`, "ERROR: 1:1: uncaught"},
+  }
+
+  for _, tt := range tests {
+    evaluated := testEval(tt.input)
+    if evaluated == nil || evaluated.Inspect() != tt.expected {
+      t.Errorf("wrong result for %q. expected=%q, got=%+v", tt.input, tt.expected, evaluated)
+    }
+  }
+}
+
+func TestBudgetErrorsAreUncatchable(t *testing.T) {
+  e := New()
+  e.MaxSteps = 100
+  input := `let loop = fn(n) { loop(n + 1) }; try { loop(0) } catch (e) { "caught" } finally { "finally" }`
+  program := parser.New(lexer.New(input)).ParseProgram()
+  testBudgetError(t, e.Eval(program, object.NewEnvironment()), "step limit exceeded (100)")
+
+  // a finally block can't return or break out of an exhausted budget.
+  finallyTests := []string{
+    `let loop = fn(n) { loop(n + 1) }; let g = fn() { try { loop(0) } finally { return "swallowed" } }; g()`,
+    `while (true) { try { while (true) {} } finally { break } }; "after"`,
+  }
+  for _, input := range finallyTests {
+    e = New()
+    e.MaxSteps = 100
+    program = parser.New(lexer.New(input)).ParseProgram()
+    testBudgetError(t, e.Eval(program, object.NewEnvironment()), "step limit exceeded (100)")
+  }
+
+  e = New()
+  e.MaxMemory = 1 << 16
+  input = `let grow = fn(s) { grow(s + s) }; try { grow("x") } catch (e) { "caught" }`
+  program = parser.New(lexer.New(input)).ParseProgram()
+  evaluated := e.Eval(program, object.NewEnvironment())
+  if errObj, ok := evaluated.(*object.Error); !ok || errObj.Kind != object.MEMORY_ERROR {
+    t.Errorf("memory error was caught. got=%+v", evaluated)
+  }
+}
+
+func TestWhileStatements(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected string  // Inspect() of the result
+  }{
+    {"let i = 0; while (i < 10) { let i = i + 1 }; i", "10"},
+    {"while (false) { 1 }", "null"},
+    {"let i = 0; while (true) { let i = i + 1; if (i == 5) { break } }; i", "5"},
+    {"let i = 0; let odd = []; while (i < 6) { let i = i + 1; if (i / 2 * 2 == i) { continue } let odd = push(odd, i) }; odd", "[1, 3, 5]"},
+    {"let f = fn() { let i = 0; while (true) { let i = i + 1; if (i == 3) { return i * 10 } } }; f()", "30"},
+    {"let i = 0; let n = 0; while (i < 3) { let i = i + 1; let j = 0; while (true) { let j = j + 1; let n = n + 1; if (j == 2) { break } } }; n", "6"},
+    {"let i = 0; while (i < 3) { let i = i + 1; try { break } finally { let i = 10 } }; i", "10"},
+    {"while (true) { 1 + true }", "ERROR: 1:18: type mismatch: INTEGER + BOOLEAN"},
+    {"let f = fn() { while (true) { let x = if (true) { return 7 } else { 0 } } }; f()", "7"},
+  }
+
+  for _, tt := range tests {
+    evaluated := testEval(tt.input)
+    if evaluated == nil || evaluated.Inspect() != tt.expected {
+      t.Errorf("wrong result for %q. expected=%q, got=%+v", tt.input, tt.expected, evaluated)
+    }
+  }
+}
+
+func TestLoopControlOutsideLoop(t *testing.T) {
+  // the parser rejects them, a hand built AST is still reported.
+  program := &ast.Program{Statements: []ast.Statement{
+    &ast.BreakStatement{Token: token.Token{Type: token.BREAK, Literal: "break", Pos: token.Position{Line: 1, Column: 1}}},
+  }}
+  evaluated := Eval(program, object.NewEnvironment())
+  if errObj, ok := evaluated.(*object.Error); !ok || errObj.Inspect() != "ERROR: 1:1: break outside of a loop" {
+    t.Errorf("break outside of a loop not reported. got=%+v", evaluated)
+  }
+}
+
+func TestWhileSpendsBudget(t *testing.T) {
+  e := New()
+  e.MaxSteps = 50
+  program := parser.New(lexer.New("while (true) { }")).ParseProgram()
+  testBudgetError(t, e.Eval(program, object.NewEnvironment()), "step limit exceeded (50)")
+}
+
+func TestForStatements(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected string  // Inspect() of the result
+  }{
+    {`let f = fn(xs) { for (x in xs) { return x * 10 } }; f([4, 5])`, "40"},
+    {`for (x in 0..3) { if (x == 2) { break } }`, "null"},
+    {`for (x in 5) { x }`, "ERROR: 1:11: cannot iterate over INTEGER"},
+    {`for (x in [1, 2]) { x + true }`, "ERROR: 1:23: type mismatch: INTEGER + BOOLEAN"},
+    {`0..5`, "0..5"},
+    {`1..<2 + 3`, "1..<5"},
+    {`"a".."b"`, "ERROR: 1:4: range bounds must be INTEGER, got STRING .. STRING"},
+  }
+
+  for _, tt := range tests {
+    evaluated := testEval(tt.input)
+    if evaluated == nil || evaluated.Inspect() != tt.expected {
+      t.Errorf("wrong result for %q. expected=%q, got=%+v", tt.input, tt.expected, evaluated)
+    }
+  }
+}
+
+func TestForIteratesElements(t *testing.T) {
+  // collect(x) records the loop variables, collected() returns them.
+  var collected []object.Object
+  builtins["collect"] = &object.Builtin{Fn: func(args ...object.Object) object.Object {
+    collected = append(collected, args...)
+    return NULL
+  }}
+  builtins["collected"] = &object.Builtin{Fn: func(args ...object.Object) object.Object {
+    return &object.Array{Elements: collected}
+  }}
+  defer delete(builtins, "collect")
+  defer delete(builtins, "collected")
+
+  tests := []struct {
+    input    string
+    expected string
+  }{
+    {`for (x in [1, 2, 3]) { collect(x) }`, "[1, 2, 3]"},
+    {`for (x in "héllo") { collect(x) }`, "[h, é, l, l, o]"},
+    {`for (x in {"b": 1, "a": 2}) { collect(x) }`, "[b, a]"},
+    {`for (x in 0..3) { collect(x) }`, "[0, 1, 2, 3]"},
+    {`for (x in 0..<3) { collect(x) }`, "[0, 1, 2]"},
+    {`for (x in 3..0) { collect(x) }`, "[]"},
+    {`for (x in 2..<2) { collect(x) }`, "[]"},
+    {`for (x in 9223372036854775806..9223372036854775807) { collect(x) }`, "[9223372036854775806, 9223372036854775807]"},
+    {`for (x in 0..5) { if (x == 1) { continue } if (x == 3) { break } collect(x) }`, "[0, 2]"},
+    // break and continue inside expressions reach the loop, they're never values.
+    {`let i = 0; while (i < 3) { i += 1; let x = if (i == 2) { break } else { i }; collect(x) }`, "[1]"},
+    {`let i = 0; while (i < 3) { i += 1; collect(if (i == 2) { continue } else { i }) }`, "[1, 3]"},
+    {`for (x in 0..3) { collect([x, if (x == 1) { continue } else { x }]) }`, "[[0, 0], [2, 2], [3, 3]]"},
+    {`for (x in 0..3) { let h = {"x": if (x == 2) { break } else { x }}; collect(h["x"]) }`, "[0, 1]"},
+    {`let y = 0; for (x in 0..3) { y = if (x == 1) { continue } else { x }; collect(y) }`, "[0, 2, 3]"},
+    {`for (x in 0..3) { collect(1 + if (x == 1) { continue } else { x }) }`, "[1, 3, 4]"},
+  }
+
+  for _, tt := range tests {
+    collected = []object.Object{}
+    testEval(tt.input)
+    if got := (&object.Array{Elements: collected}).Inspect(); got != tt.expected {
+      t.Errorf("wrong elements for %q. expected=%q, got=%q", tt.input, tt.expected, got)
+    }
+  }
+
+  // each closure captures the value of its own iteration.
+  collected = []object.Object{}
+  evaluated := testEval(`for (x in 0..<3) { collect(fn() { x }) } let fs = collected(); [fs[0](), fs[1](), fs[2]()]`)
+  if evaluated.Inspect() != "[0, 1, 2]" {
+    t.Errorf("closures share the loop variable. got=%q", evaluated.Inspect())
+  }
+}
+
+func TestAssignExpressions(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected string  // Inspect() of the result
+  }{
+    {"let x = 1; x = 2; x", "2"},
+    {"let x = 1; x = 2", "2"},
+    {"let a = 1; let b = 2; a = b = 3; [a, b]", "[3, 3]"},
+    {"let x = 10; x += 5; x -= 3; x *= 2; x /= 4; x", "6"},
+    {`let s = "a"; s += "b"; s`, "ab"},
+    {"let x = 1.5; x *= 2; x", "3.0"},
+    {"let counter = fn() { let n = 0; fn() { n += 1 } }; let c = counter(); c(); c(); c()", "3"},
+    {"let total = 0; for (x in 1..4) { total += x }; total", "10"},
+    {"let i = 0; while (i < 5) { i += 1 }; i", "5"},
+    {"let x = 1; let f = fn() { let x = 5; x = 6 }; f(); x", "1"},
+    {"let a = [1, 2, 3]; a[0] = 9; a[-1] += 10; a", "[9, 2, 13]"},
+    {"let a = [1, 2]; let b = a; b[1] = 5; a", "[1, 5]"},
+    {`let h = {"a": 1}; h["a"] += 1; h["b"] = 3; h`, "{a: 2, b: 3}"},
+    {"let grid = [[0, 0], [0, 0]]; grid[1][0] = 7; grid", "[[0, 0], [7, 0]]"},
+    {"let a = [1]; a[0] = a; a", "[[...]]"},
+    {`let h = {}; h["self"] = h; h`, "{self: {...}}"},
+    {"y = 1", "ERROR: 1:1: assignment to undefined identifier: y"},
+    {"len = 1", "ERROR: 1:1: assignment to undefined identifier: len"},
+    {"let x = 1; x += true", "ERROR: 1:14: type mismatch: INTEGER + BOOLEAN"},
+    {"let a = [1]; a[1] = 2", "ERROR: 1:15: index out of range: 1 (length 1)"},
+    {`let a = [1]; a["x"] = 2`, "ERROR: 1:15: array index must be INTEGER, got STRING"},
+    {`let h = {}; h[[]] = 2`, "ERROR: 1:14: unusable as hash key: ARRAY"},
+    {`let s = "ab"; s[0] = "c"`, "ERROR: 1:16: index assignment not supported: STRING"},
+    {`let h = {}; h["n"] += 1`, "ERROR: 1:20: type mismatch: NULL + INTEGER"},
+  }
+
+  for _, tt := range tests {
+    evaluated := testEval(tt.input)
+    if evaluated == nil || evaluated.Inspect() != tt.expected {
+      t.Errorf("wrong result for %q. expected=%q, got=%+v", tt.input, tt.expected, evaluated)
+    }
+  }
+}
+
+func TestInterpolatedStrings(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected string
+  }{
+    {`let name = "monkey"; "hello ${name}"`, "hello monkey"},
+    {`let n = 2; "you have ${n + 1} items"`, "you have 3 items"},
+    {`"${1.5} ${true} ${[1, "a"]} ${{"k": 2}} ${0..3}"`, "1.5 true [1, a] {k: 2} 0..3"},
+    {`let f = fn() {}; "${f()}"`, "null"},
+    {`"nested ${"a${1 + 1}b"}"`, "nested a2b"},
+    {`"\${literal}"`, "${literal}"},
+  }
+
+  for _, tt := range tests {
+    evaluated := testEval(tt.input)
+    str, ok := evaluated.(*object.String)
+    if !ok {
+      t.Errorf("object is not String for %q. got=%T (%+v)", tt.input, evaluated, evaluated)
+      continue
+    }
+    if str.Value != tt.expected {
+      t.Errorf("String has wrong value. expected=%q, got=%q", tt.expected, str.Value)
+    }
+  }
+
+  evaluated := testEval(`"a ${1 + true} b"`)
+  if errObj, ok := evaluated.(*object.Error); !ok || errObj.Inspect() != "ERROR: 1:8: type mismatch: INTEGER + BOOLEAN" {
+    t.Errorf("error in an interpolation not propagated. got=%+v", evaluated)
+  }
+}
@@ -0,0 +1,137 @@
+package evaluator
+
+import (
+  "Monkey/ast"
+  "Monkey/object"
+)
+
+/***** Loops *****/
+
+/* runs the body while the condition is truthy, a while statement -..
+* evaluates to null. break and continue reach the loop as signal objects -..
+* (like ReturnValue), a return or an error leaves it and propagates. -..
+* Every iteration spends a step of the execution budget. */
+func (e *Evaluator) evalWhileStatement(ws *ast.WhileStatement, env *object.Environment) object.Object {
+  for {
+    if err := e.tick(); err != nil {
+      return withPos(err, ws)
+    }
+    condition := e.eval(ws.Condition, env)
+    if isSignal(condition) {
+      return condition
+    }
+    if !isTruthy(condition) {
+      return NULL
+    }
+
+    switch result := e.eval(ws.Body, env).(type) {
+    case *object.Break:
+      return NULL
+    case *object.Continue:
+      continue
+    case *object.ReturnValue, *object.Error:
+      return result
+    }
+  }
+}
+
+/* runs the body once per element of the iterable: the characters of a -..
+* string, the elements of an array, the keys of a hash (in insertion -..
+* order) or the integers of a range. The loop variable is bound in a -..
+* fresh environment per iteration, so closures capture its value. */
+func (e *Evaluator) evalForStatement(fs *ast.ForStatement, env *object.Environment) object.Object {
+  iterable := e.eval(fs.Iterable, env)
+  if isSignal(iterable) {
+    return iterable
+  }
+
+  var result object.Object = NULL
+  err := e.iterate(iterable, func(value object.Object) bool {
+    if err := e.tick(); err != nil {
+      result = withPos(err, fs)
+      return false
+    }
+    loopEnv := object.NewEnclosedEnvironment(env)
+    loopEnv.Set(fs.Variable.Value, value)
+
+    switch evaluated := e.eval(fs.Body, loopEnv).(type) {
+    case *object.Break:
+      return false
+    case *object.ReturnValue, *object.Error:
+      result = evaluated
+      return false
+    }
+    return true
+  })
+  if err != nil {
+    return withPos(err, fs.Iterable)
+  }
+  return result
+}
+
+/* calls #fn with every element of #iterable until it returns false.
+* @return an error if #iterable can't be iterated. */
+func (e *Evaluator) iterate(iterable object.Object, fn func(object.Object) bool) object.Object {
+  switch iterable := iterable.(type) {
+  case *object.String:
+    for _, ch := range iterable.Value {
+      value := e.track(&object.String{Value: string(ch)})
+      if isError(value) {
+        return value
+      }
+      if !fn(value) {
+        break
+      }
+    }
+  case *object.Array:
+    for _, element := range iterable.Elements {
+      if !fn(element) {
+        break
+      }
+    }
+  case *object.Hash:
+    for _, key := range iterable.Keys {
+      if !fn(iterable.Pairs[key].Key) {
+        break
+      }
+    }
+  case *object.Range:
+    if iterable.Start > iterable.End || (iterable.Start == iterable.End && !iterable.Inclusive) {
+      return nil
+    }
+    for i := iterable.Start; ; i++ {
+      if i == iterable.End && !iterable.Inclusive {
+        break
+      }
+      value := &object.Integer{Value: i}
+      // checked before i++ so End == MaxInt64 doesn't overflow.
+      if !fn(value) || i == iterable.End {
+        break
+      }
+    }
+  default:
+    return newError("cannot iterate over %s", iterable.Type())
+  }
+  return nil
+}
+
+// start..end or start..<end, big integer bounds saturate to the int64 range.
+func evalRangeExpression(operator string, left, right object.Object) object.Object {
+  start, ok := left.(*object.Integer)
+  end, ok2 := right.(*object.Integer)
+  if !ok || !ok2 {
+    return newError("range bounds must be INTEGER, got %s %s %s", left.Type(), operator, right.Type())
+  }
+  return &object.Range{Start: int64Value(start), End: int64Value(end), Inclusive: operator == ".."}
+}
+
+// break/continue that escaped every loop, the parser normally rejects them.
+func loopControlError(signal object.Object) *object.Error {
+  switch signal := signal.(type) {
+  case *object.Break:
+    return &object.Error{Message: "break outside of a loop", Kind: object.RUNTIME_ERROR, Pos: signal.Pos}
+  case *object.Continue:
+    return &object.Error{Message: "continue outside of a loop", Kind: object.RUNTIME_ERROR, Pos: signal.Pos}
+  }
+  return newError("unexpected %s", signal.Type())
+}
@@ -0,0 +1,150 @@
+package evaluator
+
+import (
+  "fmt"
+  "Monkey/object"
+)
+
+/***** Allocation accounting *****/
+
+// rough shallow sizes in bytes, elements are accounted when they're created.
+const (
+  objectOverhead    = 16
+  elementSize       = 16  // an interface value
+  hashEntryOverhead = 80  // HashKey, HashPair and the Keys slot
+)
+
+/* track adds the estimated size of #obj, a value the evaluator just -..
+* created, to the allocations of the Evaluator. Accounting is opt-in, -..
+* it's skipped when MaxMemory is 0. Fixed-size scalars (see isScalar) -..
+* are temporaries that can't blow up and aren't charged, only strings, -..
+* collections and big integers are.
+* @return #obj, or a MEMORY_ERROR once MaxMemory is exceeded. */
+func (e *Evaluator) track(obj object.Object) object.Object {
+  if e.MaxMemory <= 0 || obj == nil || isScalar(obj) {
+    return obj
+  }
+  if err := e.account(sizeOf(obj)); err != nil {
+    return err
+  }
+  return obj
+}
+
+// adds #size bytes to the allocations, for growth that isn't a new object.
+func (e *Evaluator) account(size int64) *object.Error {
+  if e.MaxMemory <= 0 || size == 0 {
+    return nil
+  }
+  e.allocated += size
+  if e.allocated > e.MaxMemory {
+    return newMemoryError("memory limit exceeded (%d bytes)", e.MaxMemory)
+  }
+  return nil
+}
+
+// Allocated returns the bytes accounted so far, only tracked when MaxMemory is set.
+func (e *Evaluator) Allocated() int64 {
+  return e.allocated
+}
+
+// integers that fit an int64, floats and ranges.
+func isScalar(obj object.Object) bool {
+  switch obj := obj.(type) {
+  case *object.Integer:
+    return !obj.IsBig()
+  case *object.Float, *object.Range:
+    return true
+  }
+  return false
+}
+
+// estimated shallow size of #obj, shared and unaccounted objects weigh 0.
+func sizeOf(obj object.Object) int64 {
+  switch obj := obj.(type) {
+  case *object.String:
+    return objectOverhead + int64(len(obj.Value))
+  case *object.Integer:
+    if obj.IsBig() {
+      return objectOverhead + int64(len(obj.Big.Bits()))*8
+    }
+    return objectOverhead
+  case *object.Float, *object.Range:
+    return objectOverhead
+  case *object.Array:
+    return objectOverhead + int64(len(obj.Elements))*elementSize
+  case *object.Hash:
+    return objectOverhead + int64(len(obj.Keys))*hashEntryOverhead
+  }
+  return 0
+}
+
+/* accounts the result of a builtin. It may be one of the arguments, a -..
+* value they hold (first(), last()) or a collection of their elements, -..
+* which are already accounted, the new elements of an array (e.g. the -..
+* integers of bytes()) are accounted one by one like the elements of an -..
+* array literal. */
+func (e *Evaluator) trackBuiltinResult(result object.Object, args []object.Object) object.Object {
+  if e.MaxMemory <= 0 || result == nil || isScalar(result) || isArgumentValue(result, args) {
+    return result
+  }
+  if array, ok := result.(*object.Array); ok {
+    known := argumentValues(args)
+    for _, el := range array.Elements {
+      if known[el] {
+        continue
+      }
+      if err := e.account(sizeOf(el)); err != nil {
+        return err
+      }
+    }
+  }
+  return e.track(result)
+}
+
+// the arguments and the elements, keys and values they hold.
+func argumentValues(args []object.Object) map[object.Object]bool {
+  values := map[object.Object]bool{}
+  for _, arg := range args {
+    values[arg] = true
+    switch arg := arg.(type) {
+    case *object.Array:
+      for _, el := range arg.Elements {
+        values[el] = true
+      }
+    case *object.Hash:
+      for _, pair := range arg.Pairs {
+        values[pair.Key] = true
+        values[pair.Value] = true
+      }
+    }
+  }
+  return values
+}
+
+// whether #obj is one of #args or held by one of them, see argumentValues.
+func isArgumentValue(obj object.Object, args []object.Object) bool {
+  for _, arg := range args {
+    if obj == arg {
+      return true
+    }
+    switch arg := arg.(type) {
+    case *object.Array:
+      for _, el := range arg.Elements {
+        if obj == el {
+          return true
+        }
+      }
+    case *object.Hash:
+      for _, pair := range arg.Pairs {
+        if obj == pair.Key || obj == pair.Value {
+          return true
+        }
+      }
+    }
+  }
+  return false
+}
+
+func newMemoryError(format string, a ...interface{}) *object.Error {
+  return &object.Error{Message: fmt.Sprintf(format, a...), Kind: object.MEMORY_ERROR}
+}
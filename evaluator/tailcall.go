@@ -0,0 +1,92 @@
+package evaluator
+
+import (
+  "Monkey/ast"
+  "Monkey/object"
+)
+
+/***** Tail calls *****/
+
+/* A call in tail position of a function body isn't made right away, -..
+* it's handed back to applyFunction as a tailCall which runs it in a -..
+* loop (trampoline), so tail recursive functions run in constant stack.
+* Tail positions are the last statement of the body (through nested -..
+* blocks and if/else branches) and `return <call>` in those blocks. */
+const TAIL_CALL_OBJ = "TAIL_CALL"
+
+type tailCall struct {
+  fn   object.Object
+  args []object.Object
+  node *ast.CallExpression  // call site, for error positions
+}
+
+func (tc *tailCall) Type() object.ObjectType { return TAIL_CALL_OBJ }
+func (tc *tailCall) Inspect() string          { return "tail call " + tc.node.String() }
+
+// like Eval, but a call in tail position of #node evaluates to a tailCall.
+func (e *Evaluator) evalTail(node ast.Node, env *object.Environment) object.Object {
+  switch node := node.(type) {
+  case *ast.BlockStatement:
+    return e.evalTailBlockStatement(node, env)
+
+  case *ast.ExpressionStatement:
+    return e.evalTail(node.Expression, env)
+
+  case *ast.ReturnStatement:
+    call, ok := node.ReturnValue.(*ast.CallExpression)
+    if !ok {
+      return e.eval(node, env)
+    }
+    val := e.evalTail(call, env)
+    if isSignal(val) {
+      return val
+    }
+    return &object.ReturnValue{Value: val}
+
+  case *ast.IfExpression:
+    condition := e.eval(node.Condition, env)
+    if isSignal(condition) {
+      return condition
+    }
+    if isTruthy(condition) {
+      return e.evalTail(node.Consequence, env)
+    } else if node.Alternative != nil {
+      return e.evalTail(node.Alternative, env)
+    }
+    return NULL
+
+  case *ast.CallExpression:
+    function := e.eval(node.Function, env)
+    if isSignal(function) {
+      return function
+    }
+    args := e.evalExpressions(node.Arguments, env)
+    if len(args) == 1 && isSignal(args[0]) {
+      return args[0]
+    }
+    // builtins don't recurse, there is nothing to gain by deferring them.
+    if _, ok := function.(*object.Function); !ok {
+      return e.applyFunction(function, args, node)
+    }
+    return &tailCall{fn: function, args: args, node: node}
+
+  default:
+    return e.eval(node, env)
+  }
+}
+
+func (e *Evaluator) evalTailBlockStatement(block *ast.BlockStatement, env *object.Environment) object.Object {
+  var result object.Object
+  last := len(block.Statements) - 1
+  for i, statement := range block.Statements {
+    if _, isReturn := statement.(*ast.ReturnStatement); isReturn || i == last {
+      result = e.evalTail(statement, env)
+    } else {
+      result = e.eval(statement, env)
+    }
+    if isSignal(result) {
+      return result
+    }
+  }
+  return result
+}
@@ -0,0 +1,86 @@
+package evaluator
+
+import (
+  "Monkey/ast"
+  "Monkey/object"
+)
+
+/***** try catch finally *****/
+
+/* evaluates the try block, an error leaving it is bound to the catch -..
+* parameter as a hash (see errorToHash) and handled by the catch block. -..
+* The finally block always runs afterwards, its value is discarded -..
+* unless it's an error, a return, a break or a continue, which replace -..
+* the outcome of the try.
+* Errors that can't be caught (see isCatchable) skip the finally block, -..
+* which could otherwise return or break out of the hard stop.
+* Calls inside a try are never tail calls, the try must stay on the stack -..
+* to catch their errors. */
+func (e *Evaluator) evalTryExpression(te *ast.TryExpression, env *object.Environment) object.Object {
+  result := e.eval(te.Block, env)
+  if err, ok := result.(*object.Error); ok && !isCatchable(err) {
+    return err
+  }
+  if err, ok := result.(*object.Error); ok && te.Catch != nil {
+    catchEnv := object.NewEnclosedEnvironment(env)
+    if te.Param != nil {
+      catchEnv.Set(te.Param.Value, errorToHash(err))
+    }
+    result = e.eval(te.Catch, catchEnv)
+  }
+
+  if te.Finally != nil {
+    if finally := e.eval(te.Finally, env); isSignal(finally) {
+      return finally
+    }
+  }
+  if result == nil {
+    return NULL
+  }
+  return result
+}
+
+// an exhausted budget or an internal error must stop the program, the script can't catch it.
+func isCatchable(err *object.Error) bool {
+  return err.Kind != object.BUDGET_ERROR && err.Kind != object.MEMORY_ERROR &&
+    err.Kind != object.INTERNAL_ERROR
+}
+
+/* the value a catch block receives:
+* {"message": ..., "kind": ..., "line": ..., "column": ..., "value": ...}
+* value is the thrown value, null for runtime errors. */
+func errorToHash(err *object.Error) *object.Hash {
+  hash := object.NewHash()
+  set := func(key string, value object.Object) {
+    k := &object.String{Value: key}
+    hash.Set(k.HashKey(), object.HashPair{Key: k, Value: value})
+  }
+  set("message", &object.String{Value: err.Message})
+  set("kind", &object.String{Value: string(err.Kind)})
+  set("line", &object.Integer{Value: int64(err.Pos.Line)})
+  set("column", &object.Integer{Value: int64(err.Pos.Column)})
+  if err.Value != nil {
+    set("value", err.Value)
+  } else {
+    set("value", NULL)
+  }
+  return hash
+}
+
+/* the message of a thrown string is the string itself, a thrown hash -..
+* can carry its own "message", which makes `throw e` rethrow a caught error. */
+func newThrownError(value object.Object) *object.Error {
+  message := value.Inspect()
+  switch value := value.(type) {
+  case *object.String:
+    message = value.Value
+  case *object.Hash:
+    key := &object.String{Value: "message"}
+    if pair, ok := value.Pairs[key.HashKey()]; ok {
+      if str, ok := pair.Value.(*object.String); ok {
+        message = str.Value
+      }
+    }
+  }
+  return &object.Error{Message: message, Kind: object.THROWN_ERROR, Value: value}
+}
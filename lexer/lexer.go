@@ -1,41 +1,110 @@
 package lexer
 
-import "Monkey/token"
+import (
+  "fmt"
+  "strconv"
+  "strings"
+  "unicode"
+  "unicode/utf8"
+  "Monkey/token"
+)
 
 type Lexer struct {
   input         string
   position      int     // current position  in input (current char)
   readPosition  int     // current reading position   (after current char)
-  ch            byte    // current char that's being examined
+  ch            rune    // current char that's being examined
+  filename      string
+  line          int     // line of l.ch, starts at 1
+  column        int     // column of l.ch in characters (runes), starts at 1
+  keepComments  bool    // attach comments to the next token as trivia
+  // one entry per ${ ... } being lexed, the number of '{' open inside it.
+  templates     []int
 }
 
 func New(input string) *Lexer {
-  var l *Lexer = &Lexer{input: input}
+  return NewFile("", input)
+}
+
+// NewFile is like New but stamps #filename on every token position.
+func NewFile(filename string, input string) *Lexer {
+  var l *Lexer = &Lexer{input: input, filename: filename, line: 1}
   l.readChar()
   return l
 }
 
-func newToken(tokenType token.TokenType, ch byte) token.Token {
+/* KeepComments makes the lexer attach the comments before each token -..
+* to its Trivia, so formatters and doc generators can preserve them. -..
+* They're dropped by default. */
+func (l *Lexer) KeepComments(keep bool) {
+  l.keepComments = keep
+}
+
+func newToken(tokenType token.TokenType, ch rune) token.Token {
   return token.Token{Type: tokenType, Literal: string(ch)}
 }
 
-/* reads a character as it advances position and readPosition. */
+/* reads a character (an UTF-8 encoded rune) as it advances position -..
+* and readPosition, which are byte offsets. Invalid UTF-8 reads as -..
+* utf8.RuneError. */
 func (l *Lexer) readChar(){
+  if l.ch == '\n' {
+    l.line += 1
+    l.column = 1
+  } else {
+    l.column += 1
+  }
+  size := 0
   if l.readPosition >= len(l.input) {
     l.ch = 0
   } else {
-    l.ch = l.input[l.readPosition]
+    l.ch, size = utf8.DecodeRuneInString(l.input[l.readPosition:])
   }
   l.position = l.readPosition
-  l.readPosition += 1
+  l.readPosition += size
+  if size == 0 {
+    l.readPosition += 1
+  }
 }
 
 /* returns next character in the input without incrementing position */
-func (l* Lexer) peekChar() byte{
+func (l* Lexer) peekChar() rune{
+  if (l.readPosition >= len(l.input)) {
+    return 0;
+  }
+  ch, _ := utf8.DecodeRuneInString(l.input[l.readPosition:])
+  return ch
+}
+
+// the character after peekChar().
+func (l* Lexer) peekNextChar() rune{
   if (l.readPosition >= len(l.input)) {
     return 0;
   }
-  return l.input[l.readPosition]
+  _, size := utf8.DecodeRuneInString(l.input[l.readPosition:])
+  if (l.readPosition+size >= len(l.input)) {
+    return 0;
+  }
+  ch, _ := utf8.DecodeRuneInString(l.input[l.readPosition+size:])
+  return ch
+}
+
+// operator #op, or #assign when a '=' follows (+ or +=, < or <=).
+func (l* Lexer) withAssign(op token.TokenType, assign token.TokenType) token.Token {
+  if l.peekChar() != '=' {
+    return newToken(op, l.ch)
+  }
+  l.readChar()
+  return token.Token{Type: assign, Literal: string(assign)}
+}
+
+// && and ||, a single & or | is ILLEGAL.
+func (l* Lexer) doubled(op token.TokenType) token.Token {
+  if l.peekChar() != l.ch {
+    return newToken(token.ILLEGAL, l.ch)
+  }
+  l.readChar()
+  return token.Token{Type: op, Literal: string(op)}
 }
 
 /* reads identifier and advances lexer's position until it -..
@@ -49,18 +118,59 @@ func (l* Lexer) readIdentifier() string {
   return l.input[position:l.position]
 }
 
-func (l* Lexer) readNumber() string {
+/* reads an integer or a float literal, floats have a fraction -..
+* (3.14) and/or an exponent (1e-9, 2.5E+3).
+* NOTE: a '.' only starts a fraction when a digit follows it. */
+func (l* Lexer) readNumber() (string, token.TokenType) {
   position := l.position
+  tokenType := token.TokenType(token.INT)
   for isDigit(l.ch) {
     l.readChar()
   }
-  return l.input[position:l.position]
+  if l.ch == '.' && isDigit(l.peekChar()) {
+    tokenType = token.FLOAT
+    l.readChar()
+    for isDigit(l.ch) {
+      l.readChar()
+    }
+  }
+  if (l.ch == 'e' || l.ch == 'E') && l.isExponent() {
+    tokenType = token.FLOAT
+    l.readChar()
+    if l.ch == '+' || l.ch == '-' {
+      l.readChar()
+    }
+    for isDigit(l.ch) {
+      l.readChar()
+    }
+  }
+  return l.input[position:l.position], tokenType
 }
 
-/* turns l.ch into it's compatible token. */
+// whether the 'e' at l.ch is followed by [+-]digits.
+func (l *Lexer) isExponent() bool {
+  next := l.readPosition
+  if next < len(l.input) && (l.input[next] == '+' || l.input[next] == '-') {
+    next += 1
+  }
+  return next < len(l.input) && isDigit(rune(l.input[next]))
+}
+
+/* skips whitespace and comments, then reads the next token. */
 func (l* Lexer) NextToken() token.Token {
+  trivia, illegal := l.skipTrivia()
+  if illegal != nil {
+    return *illegal
+  }
+  tok := l.readToken()
+  tok.Trivia = trivia
+  return tok
+}
+
+/* turns l.ch into it's compatible token. */
+func (l* Lexer) readToken() token.Token {
   var tok token.Token
-  l.skipWhitespace()
+  pos := l.pos()
 
   // Token classification
   switch l.ch {
@@ -84,6 +194,22 @@ func (l* Lexer) NextToken() token.Token {
         }
     case ';':
         tok = newToken(token.SEMICOLON, l.ch)
+    case ':':
+        tok = newToken(token.COLON, l.ch)
+    case '.':
+        if l.peekChar() != '.' {
+          tok = newToken(token.ILLEGAL, l.ch)
+        } else if next := l.peekNextChar(); next == '.' || next == '<' {
+          l.readChar()
+          l.readChar()
+          tok = token.Token{Type: token.ELLIPSIS, Literal: "..."}
+          if next == '<' {
+            tok = token.Token{Type: token.RANGE_EXCLUSIVE, Literal: "..<"}
+          }
+        } else {
+          l.readChar()
+          tok = token.Token{Type: token.RANGE, Literal: ".."}
+        }
     case '(':
         tok = newToken(token.LPAREN, l.ch)
     case ')':
@@ -91,24 +217,51 @@ func (l* Lexer) NextToken() token.Token {
     case ',':
         tok = newToken(token.COMMA, l.ch)
     case '+':
-        tok = newToken(token.PLUS, l.ch)
+        tok = l.withAssign(token.PLUS, token.PLUS_ASSIGN)
     case '{':
+        if n := len(l.templates); n > 0 {
+          l.templates[n-1]++
+        }
         tok = newToken(token.LBRACE, l.ch)
     case '}':
+        n := len(l.templates)
+        if n > 0 && l.templates[n-1] == 0 {
+          // end of an interpolation, the string goes on.
+          l.templates = l.templates[:n-1]
+          tok = l.readStringToken(token.TEMPLATE_TAIL, token.TEMPLATE_MIDDLE)
+          break
+        }
+        if n > 0 {
+          l.templates[n-1]--
+        }
         tok = newToken(token.RBRACE, l.ch)
+    case '[':
+        tok = newToken(token.LBRACKET, l.ch)
+    case ']':
+        tok = newToken(token.RBRACKET, l.ch)
     case '-':
-        tok = newToken(token.MINUS, l.ch)
+        tok = l.withAssign(token.MINUS, token.MINUS_ASSIGN)
     case '/':
-        tok = newToken(token.SLASH, l.ch)
+        tok = l.withAssign(token.SLASH, token.SLASH_ASSIGN)
     case '*':
-        tok = newToken(token.ASTERISK, l.ch)
+        tok = l.withAssign(token.ASTERISK, token.ASTERISK_ASSIGN)
     case '<':
-        tok = newToken(token.LT, l.ch)
+        tok = l.withAssign(token.LT, token.LT_EQ)
     case '>':
-        tok = newToken(token.GT, l.ch)
+        tok = l.withAssign(token.GT, token.GT_EQ)
+    case '&':
+        tok = l.doubled(token.AND)
+    case '|':
+        tok = l.doubled(token.OR)
     case '"':
+        tok = l.readStringToken(token.STRING, token.TEMPLATE_HEAD)
+    case '`':
         tok.Type = token.STRING
-        tok.Literal = l.readString()
+        if value, ok := l.readRawString(); !ok {
+          tok = token.Token{Type: token.ILLEGAL, Literal: "unterminated raw string literal"}
+        } else {
+          tok.Literal = value
+        }
     case 0:
         tok.Literal = ""
         tok.Type = token.EOF
@@ -116,28 +269,136 @@ func (l* Lexer) NextToken() token.Token {
         if isLetter(l.ch){
           tok.Literal = l.readIdentifier()
           tok.Type = token.LookupIdent(tok.Literal)
+          tok.Pos = pos
           return tok
         }else if isDigit(l.ch){
-          tok.Type = token.INT 
-          tok.Literal = l.readNumber()
+          tok.Literal, tok.Type = l.readNumber()
+          tok.Pos = pos
           return tok
         }else {
           tok = newToken(token.ILLEGAL, l.ch)
         }
   }
   l.readChar()
+  tok.Pos = pos
   return tok
 }
 
-func (l *Lexer) readString() string {
+// position of the current char.
+func (l *Lexer) pos() token.Position {
+  return token.Position{Filename: l.filename, Line: l.line, Column: l.column}
+}
+
+/***** Strings *****/
+
+/* l.ch opens a string part: the quote starting a string or the '}' -..
+* ending an interpolation. #end is the type of a part that ends the -..
+* string, #interpolation of one followed by a ${. */
+func (l *Lexer) readStringToken(end token.TokenType, interpolation token.TokenType) token.Token {
+  value, err, open := l.readString()
+  if open {
+    l.templates = append(l.templates, 0)
+  }
+  if err != "" {
+    return token.Token{Type: token.ILLEGAL, Literal: err}
+  }
+  if open {
+    return token.Token{Type: interpolation, Literal: value}
+  }
+  return token.Token{Type: end, Literal: value}
+}
+
+/* reads a "..." string up to its closing quote or the next ${, -..
+* decoding the escape sequences \n \t \r \0 \\ \" \$ and \u{1F600}. -..
+* Strings may span lines, a backslash before a newline drops it.
+* @return the decoded string, a message when the literal is invalid -..
+* (the lexer turns it into an ILLEGAL token), and whether it stopped -..
+* at a ${ (l.ch is then its '{'). */
+func (l *Lexer) readString() (string, string, bool) {
+  var out strings.Builder
+  err := ""
+  for {
+    l.readChar()
+    switch l.ch {
+    case '"':
+      return out.String(), err, false
+    case 0:
+      return "", "unterminated string literal", false
+    case '$':
+      if l.peekChar() != '{' {
+        out.WriteRune(l.ch)
+        continue
+      }
+      l.readChar()
+      return out.String(), err, true
+    case '\\':
+      l.readChar()
+      if l.ch == 0 {
+        return "", "unterminated string literal", false
+      }
+      if escapeErr := l.readEscape(&out); escapeErr != "" && err == "" {
+        // keep reading up to the closing quote, so lexing resumes after the string.
+        err = escapeErr
+      }
+    default:
+      out.WriteRune(l.ch)
+    }
+  }
+}
+
+// l.ch follows a backslash, writes the character it stands for to #out.
+func (l *Lexer) readEscape(out *strings.Builder) string {
+  switch l.ch {
+  case 'n':
+    out.WriteByte('\n')
+  case 't':
+    out.WriteByte('\t')
+  case 'r':
+    out.WriteByte('\r')
+  case '0':
+    out.WriteByte(0)
+  case '\\', '"', '$':
+    out.WriteRune(l.ch)
+  case '\n':
+    // a backslash ending a line joins it with the next one.
+  case 'u':
+    if l.peekChar() != '{' {
+      return "invalid unicode escape, expected \\u{...}"
+    }
+    l.readChar()
+    start := l.position + 1
+    for isHexDigit(l.peekChar()) {
+      l.readChar()
+    }
+    digits := l.input[start:l.position+1]
+    if l.peekChar() != '}' {
+      return "invalid unicode escape, expected \\u{...}"
+    }
+    l.readChar()
+    code, err := strconv.ParseUint(digits, 16, 32)
+    if err != nil || len(digits) > 6 || !utf8.ValidRune(rune(code)) {
+      return fmt.Sprintf("invalid unicode code point \\u{%s}", digits)
+    }
+    out.WriteRune(rune(code))
+  default:
+    return fmt.Sprintf("invalid escape sequence \\%c", l.ch)
+  }
+  return ""
+}
+
+/* reads a `...` raw string, it may span lines and has no escapes.
+* @return false if the string isn't terminated. */
+func (l *Lexer) readRawString() (string, bool) {
   position := l.position + 1
   for {
     l.readChar()
-    if l.ch == '"' || l.ch == 0 {
-      break
+    if l.ch == '`' {
+      return l.input[position:l.position], true
+    }
+    if l.ch == 0 {
+      return "", false
     }
   }
-  return l.input[position:l.position]
 }
 
 func (l* Lexer) skipWhitespace() {
@@ -146,11 +407,70 @@ func (l* Lexer) skipWhitespace() {
   }
 }
 
+/***** Comments *****/
+
+/* skips whitespace and comments: // and # up to the end of the line, -..
+* and block comments, which nest.
+* @return the comments when keepComments is set, and an ILLEGAL token -..
+* if a block comment isn't terminated. */
+func (l* Lexer) skipTrivia() ([]token.Token, *token.Token) {
+  var trivia []token.Token
+  for {
+    l.skipWhitespace()
+    pos := l.pos()
+    start := l.position
+
+    switch {
+    case l.ch == '#' || (l.ch == '/' && l.peekChar() == '/'):
+      for l.ch != '\n' && l.ch != 0 {
+        l.readChar()
+      }
+    case l.ch == '/' && l.peekChar() == '*':
+      if !l.skipBlockComment() {
+        return trivia, &token.Token{Type: token.ILLEGAL, Literal: "unterminated block comment", Pos: pos}
+      }
+    default:
+      return trivia, nil
+    }
+
+    if l.keepComments {
+      literal := strings.TrimRight(l.input[start:l.position], "\r")
+      trivia = append(trivia, token.Token{Type: token.COMMENT, Literal: literal, Pos: pos})
+    }
+  }
+}
+
+// l.ch starts a block comment, stops right after its end (nested ones included).
+func (l* Lexer) skipBlockComment() bool {
+  depth := 0
+  for l.ch != 0 {
+    switch {
+    case l.ch == '/' && l.peekChar() == '*':
+      depth++
+      l.readChar()
+    case l.ch == '*' && l.peekChar() == '/':
+      depth--
+      l.readChar()
+    }
+    l.readChar()
+    if depth == 0 {
+      return true
+    }
+  }
+  return false
+}
+
 // NOTE: Adding/Removing checks would re-arrange identifier and keywords span.
-func isLetter(ch byte) bool {
-  return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+// any Unicode letter, so identifiers like größe are valid.
+func isLetter(ch rune) bool {
+  return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_' ||
+    ch > utf8.RuneSelf && unicode.IsLetter(ch)
+}
+
+func isHexDigit(ch rune) bool {
+  return isDigit(ch) || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F'
 }
 
-func isDigit(char byte) bool {
+func isDigit(char rune) bool {
   return '0' <= char && char <= '9'
 }
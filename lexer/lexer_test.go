@@ -15,7 +15,7 @@ x + y;
 }
 
 let result = add(five, ten);
-!-/*5;
+!-/ *5;
 5 < 10 > 5;
 
 if (5 < 10) {
@@ -27,6 +27,10 @@ return false;
 10 != 9;
 "foobar"
 "foo bar"
+[1, 2];
+{"foo": "bar"}
+x += 1; x -= 1; x *= 2; x /= 2;
+a <= b >= c && d || e;
 `
   tests := []struct {
     expectedType token.TokenType
@@ -106,6 +110,43 @@ return false;
     {token.SEMICOLON, ";"},
     {token.STRING, "foobar"},
     {token.STRING, "foo bar"},
+    {token.LBRACKET, "["},
+    {token.INT, "1"},
+    {token.COMMA, ","},
+    {token.INT, "2"},
+    {token.RBRACKET, "]"},
+    {token.SEMICOLON, ";"},
+    {token.LBRACE, "{"},
+    {token.STRING, "foo"},
+    {token.COLON, ":"},
+    {token.STRING, "bar"},
+    {token.RBRACE, "}"},
+    {token.IDENT, "x"},
+    {token.PLUS_ASSIGN, "+="},
+    {token.INT, "1"},
+    {token.SEMICOLON, ";"},
+    {token.IDENT, "x"},
+    {token.MINUS_ASSIGN, "-="},
+    {token.INT, "1"},
+    {token.SEMICOLON, ";"},
+    {token.IDENT, "x"},
+    {token.ASTERISK_ASSIGN, "*="},
+    {token.INT, "2"},
+    {token.SEMICOLON, ";"},
+    {token.IDENT, "x"},
+    {token.SLASH_ASSIGN, "/="},
+    {token.INT, "2"},
+    {token.SEMICOLON, ";"},
+    {token.IDENT, "a"},
+    {token.LT_EQ, "<="},
+    {token.IDENT, "b"},
+    {token.GT_EQ, ">="},
+    {token.IDENT, "c"},
+    {token.AND, "&&"},
+    {token.IDENT, "d"},
+    {token.OR, "||"},
+    {token.IDENT, "e"},
+    {token.SEMICOLON, ";"},
     {token.EOF, ""},
   }
   l := New(input)
@@ -121,3 +162,255 @@ return false;
     }
   }
 }
+
+func TestTokenPositions(t *testing.T) {
+  input := "let x = 5;\n  x + \"ab\";"
+  tests := []struct {
+    expectedLiteral string
+    expectedLine    int
+    expectedColumn  int
+  }{
+    {"let", 1, 1},
+    {"x", 1, 5},
+    {"=", 1, 7},
+    {"5", 1, 9},
+    {";", 1, 10},
+    {"x", 2, 3},
+    {"+", 2, 5},
+    {"ab", 2, 7},
+    {";", 2, 11},
+    {"", 2, 12},
+  }
+  l := NewFile("main.monkey", input)
+
+  for i, tt := range tests {
+    tok := l.NextToken()
+    if tok.Literal != tt.expectedLiteral {
+      t.Fatalf("tests[%d] - Literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+    }
+    if tok.Pos.Line != tt.expectedLine || tok.Pos.Column != tt.expectedColumn {
+      t.Fatalf("tests[%d] - position wrong. expected=%d:%d, got=%d:%d", i,
+      tt.expectedLine, tt.expectedColumn, tok.Pos.Line, tok.Pos.Column)
+    }
+    if tok.Pos.Filename != "main.monkey" {
+      t.Fatalf("tests[%d] - Filename wrong. got=%q", i, tok.Pos.Filename)
+    }
+  }
+}
+
+// identifiers may use any Unicode letter, columns count characters.
+func TestUnicodeTokens(t *testing.T) {
+  input := "let größe = \"日本\";\nπ + größe;"
+  tests := []struct {
+    expectedType    token.TokenType
+    expectedLiteral string
+    expectedColumn  int
+  }{
+    {token.LET, "let", 1},
+    {token.IDENT, "größe", 5},
+    {token.ASSIGN, "=", 11},
+    {token.STRING, "日本", 13},
+    {token.SEMICOLON, ";", 17},
+    {token.IDENT, "π", 1},
+    {token.PLUS, "+", 3},
+    {token.IDENT, "größe", 5},
+    {token.SEMICOLON, ";", 10},
+    {token.EOF, "", 11},
+  }
+  l := New(input)
+
+  for i, tt := range tests {
+    tok := l.NextToken()
+    if tok.Type != tt.expectedType {
+      t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+    }
+    if tok.Literal != tt.expectedLiteral {
+      t.Fatalf("tests[%d] - Literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+    }
+    if tok.Pos.Column != tt.expectedColumn {
+      t.Fatalf("tests[%d] - column wrong. expected=%d, got=%d", i, tt.expectedColumn, tok.Pos.Column)
+    }
+  }
+
+  if tok := New("€").NextToken(); tok.Type != token.ILLEGAL || tok.Literal != "€" {
+    t.Fatalf("non-letter symbol should be ILLEGAL. got=%q %q", tok.Type, tok.Literal)
+  }
+}
+
+func TestNumberTokens(t *testing.T) {
+  input := `3.14 1e-9 2.5E+3 10e 7. 42 ...xs 0..5 1..<n`
+  tests := []struct {
+    expectedType    token.TokenType
+    expectedLiteral string
+  }{
+    {token.FLOAT, "3.14"},
+    {token.FLOAT, "1e-9"},
+    {token.FLOAT, "2.5E+3"},
+    {token.INT, "10"},
+    {token.IDENT, "e"},
+    {token.INT, "7"},
+    {token.ILLEGAL, "."},
+    {token.INT, "42"},
+    {token.ELLIPSIS, "..."},
+    {token.IDENT, "xs"},
+    {token.INT, "0"},
+    {token.RANGE, ".."},
+    {token.INT, "5"},
+    {token.INT, "1"},
+    {token.RANGE_EXCLUSIVE, "..<"},
+    {token.IDENT, "n"},
+    {token.EOF, ""},
+  }
+  l := New(input)
+
+  for i, tt := range tests {
+    tok := l.NextToken()
+    if tok.Type != tt.expectedType {
+      t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+    }
+    if tok.Literal != tt.expectedLiteral {
+      t.Fatalf("tests[%d] - Literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+    }
+  }
+}
+
+func TestComments(t *testing.T) {
+  input := `// leading note
+let x = 5; # trailing
+/* block /* nested */ still comment */ x / 2
+x /= 1 //end`
+  tests := []struct {
+    expectedType    token.TokenType
+    expectedLiteral string
+    expectedTrivia  []string
+  }{
+    {token.LET, "let", []string{"// leading note"}},
+    {token.IDENT, "x", nil},
+    {token.ASSIGN, "=", nil},
+    {token.INT, "5", nil},
+    {token.SEMICOLON, ";", nil},
+    {token.IDENT, "x", []string{"# trailing", "/* block /* nested */ still comment */"}},
+    {token.SLASH, "/", nil},
+    {token.INT, "2", nil},
+    {token.IDENT, "x", nil},
+    {token.SLASH_ASSIGN, "/=", nil},
+    {token.INT, "1", nil},
+    {token.EOF, "", []string{"//end"}},
+  }
+
+  for _, keep := range []bool{false, true} {
+    l := New(input)
+    l.KeepComments(keep)
+    for i, tt := range tests {
+      tok := l.NextToken()
+      if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+        t.Fatalf("tests[%d] - token wrong. expected=%q %q, got=%q %q", i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+      }
+      expected := tt.expectedTrivia
+      if !keep {
+        expected = nil
+      }
+      if len(tok.Trivia) != len(expected) {
+        t.Fatalf("tests[%d] - wrong trivia. expected=%q, got=%+v", i, expected, tok.Trivia)
+      }
+      for j, comment := range tok.Trivia {
+        if comment.Type != token.COMMENT || comment.Literal != expected[j] {
+          t.Errorf("tests[%d] - trivia[%d] wrong. expected=%q, got=%q %q", i, j, expected[j], comment.Type, comment.Literal)
+        }
+      }
+    }
+  }
+
+  l := New("x /* never closed")
+  l.NextToken()
+  tok := l.NextToken()
+  if tok.Type != token.ILLEGAL || tok.Literal != "unterminated block comment" || tok.Pos.Column != 3 {
+    t.Errorf("unterminated block comment not reported. got=%+v", tok)
+  }
+  if tok = l.NextToken(); tok.Type != token.EOF {
+    t.Errorf("expected EOF after the comment. got=%q", tok.Type)
+  }
+}
+
+func TestStringTokens(t *testing.T) {
+  tests := []struct {
+    input           string
+    expectedType    token.TokenType
+    expectedLiteral string
+  }{
+    {`"plain"`, token.STRING, "plain"},
+    {`"a\"b"`, token.STRING, `a"b`},
+    {`"line\nnext\ttab\r\\"`, token.STRING, "line\nnext\ttab\r\\"},
+    {`"nul\0"`, token.STRING, "nul\x00"},
+    {`"\u{1F600} \u{e9}"`, token.STRING, "😀 é"},
+    {"`raw \\n \"quoted\"\nsecond line`", token.STRING, "raw \\n \"quoted\"\nsecond line"},
+    {`"never closed`, token.ILLEGAL, "unterminated string literal"},
+    {"\"spans\nlines\"", token.STRING, "spans\nlines"},
+    {"\"joined \\\nline\"", token.STRING, "joined line"},
+    {`"trailing backslash\`, token.ILLEGAL, "unterminated string literal"},
+    {"`never closed", token.ILLEGAL, "unterminated raw string literal"},
+    {`"bad \q escape"`, token.ILLEGAL, `invalid escape sequence \q`},
+    {`"\u{110000}"`, token.ILLEGAL, `invalid unicode code point \u{110000}`},
+    {`"\u{}"`, token.ILLEGAL, `invalid unicode code point \u{}`},
+    {`"\u00e9"`, token.ILLEGAL, `invalid unicode escape, expected \u{...}`},
+  }
+
+  for i, tt := range tests {
+    tok := New(tt.input).NextToken()
+    if tok.Type != tt.expectedType {
+      t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+    }
+    if tok.Literal != tt.expectedLiteral {
+      t.Fatalf("tests[%d] - Literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+    }
+  }
+
+  // lexing resumes after an invalid string.
+  l := New(`"bad \q" 5 "open` + "\nx")
+  expected := []token.TokenType{token.ILLEGAL, token.INT, token.ILLEGAL, token.EOF}
+  for i, tokenType := range expected {
+    if tok := l.NextToken(); tok.Type != tokenType {
+      t.Fatalf("resume[%d] - tokentype wrong. expected=%q, got=%q", i, tokenType, tok.Type)
+    }
+  }
+}
+
+func TestTemplateTokens(t *testing.T) {
+  input := `"hi ${name}, ${ {"a": n + 1}["a"] } \${x} ${"in ${x}"}!" "$5"`
+  tests := []struct {
+    expectedType    token.TokenType
+    expectedLiteral string
+  }{
+    {token.TEMPLATE_HEAD, "hi "},
+    {token.IDENT, "name"},
+    {token.TEMPLATE_MIDDLE, ", "},
+    {token.LBRACE, "{"},
+    {token.STRING, "a"},
+    {token.COLON, ":"},
+    {token.IDENT, "n"},
+    {token.PLUS, "+"},
+    {token.INT, "1"},
+    {token.RBRACE, "}"},
+    {token.LBRACKET, "["},
+    {token.STRING, "a"},
+    {token.RBRACKET, "]"},
+    {token.TEMPLATE_MIDDLE, " ${x} "},
+    {token.TEMPLATE_HEAD, "in "},
+    {token.IDENT, "x"},
+    {token.TEMPLATE_TAIL, ""},
+    {token.TEMPLATE_TAIL, "!"},
+    {token.STRING, "$5"},
+    {token.EOF, ""},
+  }
+  l := New(input)
+
+  for i, tt := range tests {
+    tok := l.NextToken()
+    if tok.Type != tt.expectedType {
+      t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q (%q)", i, tt.expectedType, tok.Type, tok.Literal)
+    }
+    if tok.Literal != tt.expectedLiteral {
+      t.Fatalf("tests[%d] - Literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+    }
+  }
+}
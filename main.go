@@ -2,12 +2,58 @@ package main
 
 import (
   "fmt"
+  "io"
   "os"
   "os/user"
+  "Monkey/evaluator"
   "Monkey/repl"
 )
 
+const USAGE = `usage:
+  monkey                        start the REPL (runs stdin when it is piped)
+  monkey run <file> [args...]   run a script, "-" reads it from stdin
+  monkey -e <code> [args...]    evaluate an inline snippet
+`
+
 func main(){
+  os.Exit(run(os.Args[1:], os.Stdin, os.Stderr))
+}
+
+/* runs the command line #args, errors go to #stderr.
+* @return the process exit status. */
+func run(args []string, stdin *os.File, stderr io.Writer) int {
+  switch {
+  case len(args) == 0 && !isTerminal(stdin):
+    return runSource("<stdin>", stdin, []string{"<stdin>"}, stderr)
+
+  case len(args) == 0:
+    startRepl()
+    return 0
+
+  case args[0] == "run" && len(args) >= 2:
+    name := args[1]
+    if name == "-" {
+      return runSource("<stdin>", stdin, args[1:], stderr)
+    }
+    file, err := os.Open(name)
+    if err != nil {
+      fmt.Fprintf(stderr, "monkey: %s\n", err)
+      return 1
+    }
+    defer file.Close()
+    return runSource(name, file, args[1:], stderr)
+
+  case args[0] == "-e" && len(args) >= 2:
+    evaluator.SetArgs(append([]string{"-e"}, args[2:]...))
+    return exitCode(repl.Run("-e", args[1], stderr))
+
+  default:
+    fmt.Fprint(stderr, USAGE)
+    return 2
+  }
+}
+
+func startRepl() {
   user, err := user.Current()
   if err != nil {
     panic(err)
@@ -17,3 +63,32 @@ func main(){
   fmt.Printf("Feel free to type in commands\n")
   repl.Start(os.Stdin, os.Stdout)
 }
+
+/* reads the whole program from #in and runs it, #argv is exposed to -..
+* the script through the argc/argv builtins.
+* @return the process exit status. */
+func runSource(name string, in io.Reader, argv []string, stderr io.Writer) int {
+  input, err := io.ReadAll(in)
+  if err != nil {
+    fmt.Fprintf(stderr, "monkey: %s\n", err)
+    return 1
+  }
+  evaluator.SetArgs(argv)
+  return exitCode(repl.Run(name, string(input), stderr))
+}
+
+func exitCode(ok bool) int {
+  if ok {
+    return 0
+  }
+  return 1
+}
+
+// stdin is a terminal when it is neither a pipe nor a redirected file.
+func isTerminal(f *os.File) bool {
+  info, err := f.Stat()
+  if err != nil {
+    return false
+  }
+  return info.Mode() & os.ModeCharDevice != 0
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+  "bytes"
+  "os"
+  "path/filepath"
+  "strings"
+  "testing"
+)
+
+func TestRunExitStatus(t *testing.T) {
+  dir := t.TempDir()
+  script := func(name string, source string) string {
+    path := filepath.Join(dir, name)
+    if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+      t.Fatal(err)
+    }
+    return path
+  }
+  clean := script("clean.monkey", "let x = 1;\nx + 1;")
+  runtime := script("runtime.monkey", "let f = fn(x) { x + \"a\" };\nf(1);")
+  parse := script("parse.monkey", "let x = 1;\nlet y = add(x, 2;")
+
+  tests := []struct {
+    args     []string
+    status   int
+    expected []string  // parts of the error output
+  }{
+    {[]string{"run", clean}, 0, nil},
+    {[]string{"-e", "1 + 2"}, 0, nil},
+    {[]string{"run", runtime}, 1, []string{
+      "Traceback (most recent call last):",
+      "line 2, column 2, in <program>\n    f(1);",
+      "RuntimeError: type mismatch: INTEGER + STRING",
+    }},
+    {[]string{"run", parse}, 1, []string{
+      "parse.monkey:2:17: error[unexpected-token]: expected next token to be ), got ; instead\n" +
+      "  |\n" +
+      "2 | let y = add(x, 2;\n" +
+      "  |                 ^\n",
+    }},
+    {[]string{"run", filepath.Join(dir, "missing.monkey")}, 1, []string{"monkey: open "}},
+    {[]string{"run"}, 2, []string{"usage:"}},
+  }
+
+  for _, tt := range tests {
+    var stderr bytes.Buffer
+    status := run(tt.args, nil, &stderr)
+    if status != tt.status {
+      t.Errorf("wrong exit status for %q. expected=%d, got=%d (%s)", tt.args, tt.status, status, stderr.String())
+    }
+    if tt.expected == nil && stderr.Len() != 0 {
+      t.Errorf("unexpected error output for %q: %s", tt.args, stderr.String())
+    }
+    for _, part := range tt.expected {
+      if !strings.Contains(stderr.String(), part) {
+        t.Errorf("error output of %q doesn't contain %q. got=\n%s", tt.args, part, stderr.String())
+      }
+    }
+  }
+}
@@ -25,6 +25,20 @@ func (e *Environment) Set(name string, val Object) Object {
   return val
 }
 
+/* Assign updates the existing binding of #name, in this environment or -..
+* the closest outer one that defines it.
+* @return false if #name isn't defined. */
+func (e *Environment) Assign(name string, val Object) bool {
+  if _, ok := e.store[name]; ok {
+    e.store[name] = val
+    return true
+  }
+  if e.outer != nil {
+    return e.outer.Assign(name, val)
+  }
+  return false
+}
+
 // We create a new environment for functions in order to prevent data override.
 func NewEnclosedEnvironment(outer *Environment) *Environment {
   env := NewEnvironment()
@@ -3,14 +3,19 @@ package object
 import (
   "fmt"
   "bytes"
+  "hash/fnv"
+  "math/big"
+  "strconv"
   "strings"
   "Monkey/ast"
+  "Monkey/token"
 ) 
 
 /****** object will is a wrapper of all types in monkey *****/
 
 const (
   INTEGER_OBJ = "INTEGER"
+  FLOAT_OBJ   = "FLOAT"
   BOOLEAN_OBJ = "BOOLEAN"
   NULL_OBJ    = "NULL"
   RETURN_VALUE_OBJ = "RETURN_VALUE"
@@ -18,6 +23,11 @@ const (
   FUNCTION_OBJ = "FUNCTION"
   STRING_OBJ = "STRING"
   BUILTIN_OBJ = "BUILTIN"
+  ARRAY_OBJ = "ARRAY"
+  HASH_OBJ = "HASH"
+  RANGE_OBJ = "RANGE"
+  BREAK_OBJ = "BREAK"
+  CONTINUE_OBJ = "CONTINUE"
 )
 
 type ObjectType string
@@ -35,13 +45,87 @@ type ReturnValue struct {
 func (rv *ReturnValue) Type() ObjectType { return RETURN_VALUE_OBJ }
 func (rv *ReturnValue) Inspect() string  { return rv.Value.Inspect() }
 
+// integers from Start to End, 0..n includes n and 0..<n doesn't.
+type Range struct {
+  Start     int64
+  End       int64
+  Inclusive bool
+}
+
+func (r *Range) Type() ObjectType { return RANGE_OBJ }
+func (r *Range) Inspect() string  {
+  if r.Inclusive {
+    return fmt.Sprintf("%d..%d", r.Start, r.End)
+  }
+  return fmt.Sprintf("%d..<%d", r.Start, r.End)
+}
+
+// break and continue keywords, they unwind blocks up to the enclosing loop.
+type Break struct {
+  Pos token.Position  // of the break statement
+}
+
+func (b *Break) Type() ObjectType { return BREAK_OBJ }
+func (b *Break) Inspect() string  { return "break" }
+
+type Continue struct {
+  Pos token.Position  // of the continue statement
+}
+
+func (c *Continue) Type() ObjectType { return CONTINUE_OBJ }
+func (c *Continue) Inspect() string  { return "continue" }
+
 // -------
+
+/* Integers are int64 until an operation overflows, then they are -..
+* transparently promoted to Big (and demoted back once they fit again).
+* Value is meaningless while Big is set. */
 type Integer struct {
   Value int64
+  Big   *big.Int
+}
+
+// NewBigInteger wraps #value, demoting it to an int64 when it fits.
+func NewBigInteger(value *big.Int) *Integer {
+  if value.IsInt64() {
+    return &Integer{Value: value.Int64()}
+  }
+  return &Integer{Big: value}
+}
+
+func (i *Integer) IsBig() bool { return i.Big != nil }
+
+// BigInt returns the value as a new big.Int that is safe to modify.
+func (i *Integer) BigInt() *big.Int {
+  if i.Big != nil {
+    return new(big.Int).Set(i.Big)
+  }
+  return big.NewInt(i.Value)
 }
 
 func (i *Integer) Type() ObjectType { return INTEGER_OBJ }
-func (i *Integer) Inspect() string  { return fmt.Sprintf("%d", i.Value) }
+func (i *Integer) Inspect() string  {
+  if i.Big != nil {
+    return i.Big.String()
+  }
+  return fmt.Sprintf("%d", i.Value)
+}
+
+// -------
+type Float struct {
+  Value float64
+}
+
+func (f *Float) Type() ObjectType { return FLOAT_OBJ }
+
+// always shows a fraction or an exponent so floats don't look like integers.
+func (f *Float) Inspect() string  {
+  s := strconv.FormatFloat(f.Value, 'g', -1, 64)
+  if !strings.ContainsAny(s, ".eIN") {
+    s += ".0"
+  }
+  return s
+}
 
 // -------
 type String struct {
@@ -68,8 +152,11 @@ func (n *Null) Inspect() string  { return "null"}
 // -------
 type Function struct {
   Parameters []*ast.Identifier
+  Defaults     []ast.Expression  // nil entries for required parameters
+  Rest         *ast.Identifier   // nil unless the function is variadic
   Body         *ast.BlockStatement
   Env          *Environment
+  Name         string            // empty for anonymous functions
 }
 
 
@@ -77,8 +164,15 @@ func (f *Function) Type() ObjectType {return FUNCTION_OBJ}
 func (f *Function) Inspect() string {
   var out bytes.Buffer
   params := []string{}
-  for _, p := range f.Parameters {
-    params = append(params, p.String())
+  for i, p := range f.Parameters {
+    if i < len(f.Defaults) && f.Defaults[i] != nil {
+      params = append(params, p.String()+" = "+f.Defaults[i].String())
+    } else {
+      params = append(params, p.String())
+    }
+  }
+  if f.Rest != nil {
+    params = append(params, "..."+f.Rest.String())
   }
   out.WriteString("fn")
   out.WriteString("(")
@@ -98,9 +192,161 @@ func (b* Builtin) Type() ObjectType {return BUILTIN_OBJ}
 func (b* Builtin) Inspect() string {return "Builtin function"}
 
 // -------
+type Array struct {
+  Elements []Object
+}
+
+func (a *Array) Type() ObjectType { return ARRAY_OBJ }
+func (a *Array) Inspect() string { return inspect(a, map[Object]bool{}) }
+
+// -------
+
+// objects that can be used as hash keys.
+type Hashable interface {
+  HashKey() HashKey
+}
+
+type HashKey struct {
+  Type  ObjectType
+  Value uint64
+}
+
+func (i *Integer) HashKey() HashKey {
+  if i.Big != nil {
+    h := fnv.New64a()
+    h.Write([]byte(i.Big.String()))
+    return HashKey{Type: i.Type(), Value: h.Sum64()}
+  }
+  return HashKey{Type: i.Type(), Value: uint64(i.Value)}
+}
+
+func (b *Boolean) HashKey() HashKey {
+  var value uint64
+  if b.Value {
+    value = 1
+  }
+  return HashKey{Type: b.Type(), Value: value}
+}
+
+func (s *String) HashKey() HashKey {
+  h := fnv.New64a()
+  h.Write([]byte(s.Value))
+  return HashKey{Type: s.Type(), Value: h.Sum64()}
+}
+
+type HashPair struct {
+  Key   Object
+  Value Object
+}
+
+// Pairs are looked up by HashKey, Keys remembers the insertion order.
+type Hash struct {
+  Pairs map[HashKey]HashPair
+  Keys  []HashKey
+}
+
+func NewHash() *Hash {
+  return &Hash{Pairs: make(map[HashKey]HashPair), Keys: []HashKey{}}
+}
+
+// Set inserts a pair, an existing key keeps its position.
+func (h *Hash) Set(key HashKey, pair HashPair) {
+  if _, exists := h.Pairs[key]; !exists {
+    h.Keys = append(h.Keys, key)
+  }
+  h.Pairs[key] = pair
+}
+
+func (h *Hash) Delete(key HashKey) {
+  if _, exists := h.Pairs[key]; !exists {
+    return
+  }
+  delete(h.Pairs, key)
+  for i, k := range h.Keys {
+    if k == key {
+      h.Keys = append(h.Keys[:i:i], h.Keys[i+1:]...)
+      break
+    }
+  }
+}
+
+func (h *Hash) Type() ObjectType { return HASH_OBJ }
+func (h *Hash) Inspect() string { return inspect(h, map[Object]bool{}) }
+
+/* collections can contain themselves once they're assigned to -..
+* (a[0] = a), a collection that is already being inspected is shown -..
+* as [...] or {...} instead of recursing forever. */
+func inspect(obj Object, seen map[Object]bool) string {
+  var out bytes.Buffer
+  switch obj := obj.(type) {
+  case *Array:
+    if seen[obj] {
+      return "[...]"
+    }
+    seen[obj] = true
+    defer delete(seen, obj)
+
+    elements := []string{}
+    for _, el := range obj.Elements {
+      elements = append(elements, inspect(el, seen))
+    }
+    out.WriteString("[")
+    out.WriteString(strings.Join(elements, ", "))
+    out.WriteString("]")
+  case *Hash:
+    if seen[obj] {
+      return "{...}"
+    }
+    seen[obj] = true
+    defer delete(seen, obj)
+
+    pairs := []string{}
+    for _, key := range obj.Keys {
+      pair := obj.Pairs[key]
+      pairs = append(pairs, fmt.Sprintf("%s: %s", inspect(pair.Key, seen), inspect(pair.Value, seen)))
+    }
+    out.WriteString("{")
+    out.WriteString(strings.Join(pairs, ", "))
+    out.WriteString("}")
+  default:
+    return obj.Inspect()
+  }
+  return out.String()
+}
+
+// -------
+type ErrorKind string
+
+const (
+  RUNTIME_ERROR ErrorKind = "RuntimeError"
+  // the execution budget (steps, deadline, cancellation) ran out.
+  BUDGET_ERROR  ErrorKind = "BudgetExceeded"
+  // the allocations of the program went past the configured cap.
+  MEMORY_ERROR  ErrorKind = "MemoryLimitExceeded"
+  // raised by a throw statement.
+  THROWN_ERROR  ErrorKind = "Error"
+  // a Go panic recovered by the evaluator, a bug of the host or of a builtin.
+  INTERNAL_ERROR ErrorKind = "InternalError"
+)
+
 type Error struct {
   Message string
+  Kind    ErrorKind
+  Pos     token.Position  // node that raised the error
+  Frames  []Frame         // calls the error propagated through, innermost first
+  Value   Object          // value of the throw statement, nil for runtime errors
+}
+
+// Frame is one call of a Monkey function on the stack of an Error.
+type Frame struct {
+  Function string          // "" for anonymous functions
+  Pos      token.Position  // call site
 }
 
 func (e *Error) Type() ObjectType { return ERROR_OBJ }
-func (e *Error) Inspect() string  { return "ERROR: " + e.Message }
+func (e *Error) Inspect() string  {
+  if e.Pos.IsValid() {
+    return "ERROR: " + e.Pos.String() + ": " + e.Message
+  }
+  return "ERROR: " + e.Message
+}
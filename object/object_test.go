@@ -0,0 +1,109 @@
+package object
+
+import (
+  "testing"
+  "Monkey/token"
+)
+
+func TestStringHashKey(t *testing.T) {
+  hello1 := &String{Value: "Hello World"}
+  hello2 := &String{Value: "Hello World"}
+  diff1 := &String{Value: "My name is johnny"}
+  diff2 := &String{Value: "My name is johnny"}
+
+  if hello1.HashKey() != hello2.HashKey() {
+    t.Errorf("strings with same content have different hash keys")
+  }
+  if diff1.HashKey() != diff2.HashKey() {
+    t.Errorf("strings with same content have different hash keys")
+  }
+  if hello1.HashKey() == diff1.HashKey() {
+    t.Errorf("strings with different content have same hash keys")
+  }
+}
+
+func TestHashKeepsInsertionOrder(t *testing.T) {
+  hash := NewHash()
+  keys := []*String{{Value: "b"}, {Value: "a"}, {Value: "c"}}
+  for i, key := range keys {
+    hash.Set(key.HashKey(), HashPair{Key: key, Value: &Integer{Value: int64(i)}})
+  }
+  hash.Set(keys[0].HashKey(), HashPair{Key: keys[0], Value: &Integer{Value: 9}})
+  hash.Delete(keys[1].HashKey())
+
+  if hash.Inspect() != "{b: 9, c: 2}" {
+    t.Errorf("hash.Inspect() wrong. got=%s", hash.Inspect())
+  }
+}
+
+func TestErrorTraceback(t *testing.T) {
+  source := "let f = fn(x) {\n  x + \"a\"\n};\nf(1);"
+  at := func(line, column int) token.Position {
+    return token.Position{Filename: "main.monkey", Line: line, Column: column}
+  }
+  err := &Error{
+    Message: "type mismatch: INTEGER + STRING",
+    Kind:    RUNTIME_ERROR,
+    Pos:     at(2, 5),
+    Frames:  []Frame{{Function: "f", Pos: at(4, 2)}},
+  }
+
+  expected := `Traceback (most recent call last):
+  File "main.monkey", line 4, column 2, in <program>
+    f(1);
+  File "main.monkey", line 2, column 5, in f
+    x + "a"
+RuntimeError: type mismatch: INTEGER + STRING
+`
+  if got := err.Traceback(source); got != expected {
+    t.Errorf("wrong traceback. expected=\n%s\ngot=\n%s", expected, got)
+  }
+
+  // runaway recursion, identical frames are collapsed.
+  err = &Error{Message: "boom", Kind: RUNTIME_ERROR, Pos: at(2, 5)}
+  for i := 0; i < 10; i++ {
+    err.Frames = append(err.Frames, Frame{Pos: at(2, 1)})
+  }
+  expected = `Traceback (most recent call last):
+  File "main.monkey", line 2, column 1, in <program>
+  File "main.monkey", line 2, column 1, in <anonymous>
+  File "main.monkey", line 2, column 1, in <anonymous>
+  [Previous line repeated 7 more times]
+  File "main.monkey", line 2, column 5, in <anonymous>
+RuntimeError: boom
+`
+  if got := err.Traceback(""); got != expected {
+    t.Errorf("wrong traceback. expected=\n%s\ngot=\n%s", expected, got)
+  }
+
+  // frames without a position don't quote a source line.
+  err = &Error{Message: "boom", Kind: RUNTIME_ERROR, Pos: at(2, 5), Frames: []Frame{{Function: "f"}}}
+  expected = `Traceback (most recent call last):
+  File "<unknown>", in <program>
+  File "main.monkey", line 2, column 5, in f
+    x + "a"
+RuntimeError: boom
+`
+  if got := err.Traceback(source); got != expected {
+    t.Errorf("wrong traceback. expected=\n%s\ngot=\n%s", expected, got)
+  }
+}
+
+func TestEnvironmentAssign(t *testing.T) {
+  outer := NewEnvironment()
+  outer.Set("x", &Integer{Value: 1})
+  inner := NewEnclosedEnvironment(outer)
+
+  if !inner.Assign("x", &Integer{Value: 2}) {
+    t.Fatalf("Assign did not find x in the outer environment")
+  }
+  if x, _ := outer.Get("x"); x.(*Integer).Value != 2 {
+    t.Errorf("outer x not updated. got=%s", x.Inspect())
+  }
+  if inner.Assign("y", &Integer{Value: 3}) {
+    t.Errorf("Assign defined an undefined identifier")
+  }
+  if _, ok := inner.Get("y"); ok {
+    t.Errorf("y is defined after a failed Assign")
+  }
+}
@@ -0,0 +1,87 @@
+package object
+
+import (
+  "bytes"
+  "fmt"
+  "strings"
+  "Monkey/token"
+)
+
+// identical frames in a row past this count are collapsed, e.g. by a runaway recursion.
+const MAX_REPEATED_FRAMES = 3
+
+/* Traceback formats the error like Python does, outermost call first, -..
+* quoting the lines of #source the frames point at (source may be "" -..
+* when it isn't available, e.g. in the REPL):
+*
+*   Traceback (most recent call last):
+*     File "main.monkey", line 3, column 1, in <program>
+*       outer(1)
+*     File "main.monkey", line 1, column 22, in outer
+*       x + "a"
+*   RuntimeError: type mismatch: INTEGER + STRING
+* */
+func (e *Error) Traceback(source string) string {
+  var out bytes.Buffer
+  lines := strings.Split(source, "\n")
+  out.WriteString("Traceback (most recent call last):\n")
+
+  caller := "<program>"
+  repeated := 0
+  for i := len(e.Frames) - 1; i >= 0; i-- {
+    frame := e.Frames[i]
+    if i < len(e.Frames) - 1 && frame == e.Frames[i+1] {
+      repeated++
+      if repeated >= MAX_REPEATED_FRAMES {
+        continue
+      }
+    } else {
+      writeRepeated(&out, repeated)
+      repeated = 0
+    }
+    writeFrame(&out, lines, frame.Pos, caller)
+    caller = functionName(frame.Function)
+  }
+  writeRepeated(&out, repeated)
+  if e.Pos.IsValid() {
+    writeFrame(&out, lines, e.Pos, caller)
+  }
+
+  kind := e.Kind
+  if kind == "" {
+    kind = RUNTIME_ERROR
+  }
+  out.WriteString(fmt.Sprintf("%s: %s\n", kind, e.Message))
+  return out.String()
+}
+
+// a frame without a position is shown as File "<unknown>", in <fn>.
+func writeFrame(out *bytes.Buffer, lines []string, pos token.Position, function string) {
+  if !pos.IsValid() {
+    out.WriteString(fmt.Sprintf("  File \"<unknown>\", in %s\n", function))
+    return
+  }
+  filename := pos.Filename
+  if filename == "" {
+    filename = "<stdin>"
+  }
+  out.WriteString(fmt.Sprintf("  File %q, line %d, column %d, in %s\n", filename, pos.Line, pos.Column, function))
+  if pos.Line <= len(lines) {
+    if line := strings.TrimSpace(lines[pos.Line-1]); line != "" {
+      out.WriteString("    " + line + "\n")
+    }
+  }
+}
+
+func writeRepeated(out *bytes.Buffer, repeated int) {
+  if more := repeated - MAX_REPEATED_FRAMES + 1; more > 0 {
+    out.WriteString(fmt.Sprintf("  [Previous line repeated %d more times]\n", more))
+  }
+}
+
+func functionName(name string) string {
+  if name == "" {
+    return "<anonymous>"
+  }
+  return name
+}
@@ -0,0 +1,206 @@
+package parser
+
+import (
+  "Monkey/token"
+  "bytes"
+  "fmt"
+  "strings"
+  "unicode/utf8"
+)
+
+type Severity int
+
+const (
+  ERROR Severity = iota
+  WARNING
+)
+
+func (s Severity) String() string {
+  if s == WARNING {
+    return "warning"
+  }
+  return "error"
+}
+
+// Diagnostic codes, stable identifiers tooling can match on.
+const (
+  UNEXPECTED_TOKEN = "unexpected-token"
+  NO_PREFIX_PARSE  = "no-prefix-parse"
+  INVALID_INTEGER  = "invalid-integer"
+  INVALID_FLOAT    = "invalid-float"
+  REQUIRED_AFTER_OPTIONAL = "required-after-optional"
+  TRY_WITHOUT_HANDLER     = "try-without-handler"
+  OUTSIDE_LOOP            = "outside-loop"
+  INVALID_ASSIGNMENT      = "invalid-assignment"
+  ILLEGAL_TOKEN           = "illegal-token"
+)
+
+/* A Diagnostic describes a problem found while parsing, -..
+* Start and End delimit the offending source span (End is exclusive).
+* Expected/Actual are only set for UNEXPECTED_TOKEN. */
+type Diagnostic struct {
+  Severity Severity
+  Code     string
+  Message  string
+  Start    token.Position
+  End      token.Position
+  Expected token.TokenType
+  Actual   token.TokenType
+  Hint     string   // optional suggestion on how to fix the problem
+}
+
+// "line:column: message", the form returned by Parser.Errors().
+func (d Diagnostic) String() string {
+  if d.Start.IsValid() {
+    return d.Start.String() + ": " + d.Message
+  }
+  return d.Message
+}
+
+/* Render formats the diagnostic like a modern compiler, quoting the -..
+* offending line of #source and underlining the span with carets:
+*
+*   main.monkey:1:7: error[unexpected-token]: expected next token to be =, got INT instead
+*     |
+*   1 | let x 5;
+*     |       ^
+*     = hint: ...
+* */
+func (d Diagnostic) Render(source string) string {
+  var out bytes.Buffer
+  out.WriteString(fmt.Sprintf("%s: %s[%s]: %s\n", d.Start, d.Severity, d.Code, d.Message))
+
+  lines := strings.Split(source, "\n")
+  if d.Start.IsValid() && d.Start.Line <= len(lines) {
+    line := strings.TrimRight(lines[d.Start.Line-1], "\r")
+    gutter := fmt.Sprintf("%d", d.Start.Line)
+    blank := strings.Repeat(" ", len(gutter))
+
+    out.WriteString(blank + " |\n")
+    out.WriteString(gutter + " | " + line + "\n")
+    out.WriteString(blank + " | " + underline(line, d.Start, d.End) + "\n")
+  }
+  if d.Hint != "" {
+    out.WriteString(fmt.Sprintf("%s = hint: %s\n", strings.Repeat(" ", len(fmt.Sprintf("%d", d.Start.Line))), d.Hint))
+  }
+  return out.String()
+}
+
+// carets below [start, end) of #line, tabs are kept so the carets stay aligned.
+func underline(line string, start token.Position, end token.Position) string {
+  var out bytes.Buffer
+  // columns count runes, not bytes.
+  from := start.Column - 1
+  for _, ch := range line {
+    if from == 0 {
+      break
+    }
+    if ch == '\t' {
+      out.WriteByte('\t')
+    } else {
+      out.WriteByte(' ')
+    }
+    from--
+  }
+  width := 1
+  if end.Line == start.Line && end.Column > start.Column {
+    width = end.Column - start.Column
+  }
+  out.WriteString(strings.Repeat("^", width))
+  return out.String()
+}
+
+// position right after #tok, on the same line.
+func tokenEnd(tok token.Token) token.Position {
+  end := tok.Pos
+  width := utf8.RuneCountInString(tok.Literal)
+  if tok.Type == token.STRING {
+    width += 2 // quotes
+  }
+  if width == 0 {
+    width = 1
+  }
+  end.Column += width
+  return end
+}
+
+/***** Diagnostics reporting *****/
+
+func (p *Parser) Diagnostics() []Diagnostic {
+  return p.diagnostics
+}
+
+// duplicates at an already reported position are dropped.
+func (p *Parser) report(d Diagnostic) {
+  for _, reported := range p.diagnostics {
+    if reported.Start == d.Start {
+      return
+    }
+  }
+  p.diagnostics = append(p.diagnostics, d)
+}
+
+// an ILLEGAL token is reported for what it is, not as an unexpected one.
+func (p* Parser) peekError(t token.TokenType) {
+  if p.peekTokenIs(token.ILLEGAL) {
+    p.illegalTokenError(p.peekToken)
+    return
+  }
+  d := Diagnostic{
+    Severity: ERROR,
+    Code:     UNEXPECTED_TOKEN,
+    Message:  fmt.Sprintf("expected next token to be %s, got %s instead", t, p.peekToken.Type),
+    Start:    p.peekToken.Pos,
+    End:      tokenEnd(p.peekToken),
+    Expected: t,
+    Actual:   p.peekToken.Type,
+  }
+  switch t {
+  case token.RPAREN, token.RBRACE, token.RBRACKET:
+    d.Hint = fmt.Sprintf("insert the missing `%s`", t)
+  case token.ASSIGN:
+    d.Hint = "let statements take the form `let <name> = <expression>;`"
+  case token.TEMPLATE_TAIL:
+    d.Hint = "close the interpolation with a `}`"
+  }
+  p.report(d)
+}
+
+/* the literal of an ILLEGAL token is either the unknown character or -..
+* the lexer's description of the problem (e.g. an unterminated string). */
+func (p *Parser) illegalTokenError(tok token.Token) {
+  d := Diagnostic{
+    Severity: ERROR,
+    Code:     ILLEGAL_TOKEN,
+    Message:  tok.Literal,
+    Start:    tok.Pos,
+    End:      token.Position{Filename: tok.Pos.Filename, Line: tok.Pos.Line, Column: tok.Pos.Column + 1},
+  }
+  if utf8.RuneCountInString(tok.Literal) == 1 {
+    d.Message = fmt.Sprintf("illegal character %q", tok.Literal)
+  }
+  switch {
+  case strings.HasPrefix(tok.Literal, "unterminated string"):
+    d.Hint = "close the string with a `\"`"
+  case strings.HasPrefix(tok.Literal, "invalid escape"):
+    d.Hint = "valid escapes are \\n \\t \\r \\0 \\\\ \\\" and \\u{1F600}"
+  case tok.Literal == "&" || tok.Literal == "|":
+    d.Hint = fmt.Sprintf("did you mean `%s%s`?", tok.Literal, tok.Literal)
+  }
+  p.report(d)
+}
+
+func (p *Parser) noPrefixParseFnError(t token.TokenType) {
+  hint := fmt.Sprintf("expected an expression, `%s` cannot start one", p.curToken.Literal)
+  if t == token.EOF {
+    hint = "the input ended where an expression was expected"
+  }
+  p.report(Diagnostic{
+    Severity: ERROR,
+    Code:     NO_PREFIX_PARSE,
+    Message:  fmt.Sprintf("no prefix parse function for %s found", t),
+    Start:    p.curToken.Pos,
+    End:      tokenEnd(p.curToken),
+    Hint:     hint,
+  })
+}
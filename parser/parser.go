@@ -5,30 +5,48 @@ import (
   "Monkey/lexer"
   "Monkey/token"
   "fmt"
+  "math/big"
   "strconv"
 )
 
 var precendences = map[token.TokenType]int {
+  token.ASSIGN:          ASSIGN,
+  token.PLUS_ASSIGN:     ASSIGN,
+  token.MINUS_ASSIGN:    ASSIGN,
+  token.ASTERISK_ASSIGN: ASSIGN,
+  token.SLASH_ASSIGN:    ASSIGN,
+  token.OR:       LOGICAL_OR,
+  token.AND:      LOGICAL_AND,
   token.EQ:       EQUALS,
   token.NOT_EQ:   EQUALS,
   token.LT:       LESSGREATER,
   token.GT:       LESSGREATER,
+  token.LT_EQ:    LESSGREATER,
+  token.GT_EQ:    LESSGREATER,
+  token.RANGE:    RANGE,
+  token.RANGE_EXCLUSIVE: RANGE,
   token.PLUS:     SUM,
   token.MINUS:    SUM,
   token.SLASH:    PRODUCT,
   token.ASTERISK: PRODUCT,
   token.LPAREN:   CALL,
+  token.LBRACKET: INDEX,
 }
 
 const (
   _ int = iota
   LOWEST
+  ASSIGN      // x = y OR x += y
+  LOGICAL_OR  // ||
+  LOGICAL_AND // &&
   EQUALS      // ==
   LESSGREATER // > OR <
+  RANGE       // 0..n OR 0..<n
   SUM         // +
   PRODUCT     // *
   PREFIX      // -X OR !X
   CALL        // myFunction(X)
+  INDEX       // array[index]
 )
 
 
@@ -47,18 +65,21 @@ type Parser struct {
   // according to Pratt's Parsing algorithm.
   prefixParseFns map[token.TokenType]prefixParseFn
   infixParseFns  map[token.TokenType]infixParseFn
-  errors []string
+  diagnostics    []Diagnostic
+  blockDepth     int    // number of enclosing block statements.
+  loopDepth      int    // number of enclosing loops in the current function.
 }
 
 func New(l* lexer.Lexer) *Parser {
   p := &Parser{
     l: l,
-    errors: []string{},
+    diagnostics: []Diagnostic{},
   }
 
   p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
   p.registerPrefix(token.IDENT, p.parseIdentifier)
   p.registerPrefix(token.INT, p.parseIntegerLiteral)
+  p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
   p.registerPrefix(token.BANG, p.parsePrefixExpression)
   p.registerPrefix(token.MINUS, p.parsePrefixExpression)
   p.registerPrefix(token.TRUE, p.parseBoolean)
@@ -67,6 +88,11 @@ func New(l* lexer.Lexer) *Parser {
   p.registerPrefix(token.IF, p.parseIfExpression)
   p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
   p.registerPrefix(token.STRING,  p.parseStringLiteral)
+  p.registerPrefix(token.TEMPLATE_HEAD, p.parseInterpolatedString)
+  p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
+  p.registerPrefix(token.LBRACE, p.parseHashLiteral)
+  p.registerPrefix(token.TRY, p.parseTryExpression)
+  p.registerPrefix(token.ILLEGAL, p.parseIllegal)
 
   p.infixParseFns = make(map[token.TokenType]infixParseFn)
   p.registerInfix(token.PLUS, p.parseInfixExpression)
@@ -77,7 +103,19 @@ func New(l* lexer.Lexer) *Parser {
   p.registerInfix(token.NOT_EQ, p.parseInfixExpression)
   p.registerInfix(token.LT, p.parseInfixExpression)
   p.registerInfix(token.GT, p.parseInfixExpression)
+  p.registerInfix(token.LT_EQ, p.parseInfixExpression)
+  p.registerInfix(token.GT_EQ, p.parseInfixExpression)
+  p.registerInfix(token.AND, p.parseInfixExpression)
+  p.registerInfix(token.OR, p.parseInfixExpression)
+  p.registerInfix(token.RANGE, p.parseInfixExpression)
+  p.registerInfix(token.RANGE_EXCLUSIVE, p.parseInfixExpression)
+  p.registerInfix(token.ASSIGN, p.parseAssignExpression)
+  p.registerInfix(token.PLUS_ASSIGN, p.parseAssignExpression)
+  p.registerInfix(token.MINUS_ASSIGN, p.parseAssignExpression)
+  p.registerInfix(token.ASTERISK_ASSIGN, p.parseAssignExpression)
+  p.registerInfix(token.SLASH_ASSIGN, p.parseAssignExpression)
   p.registerInfix(token.LPAREN, p.parseCallExpression)
+  p.registerInfix(token.LBRACKET, p.parseIndexExpression)
 
   // Read two tokens to set curToken and peekToken.
   p.nextToken()
@@ -99,20 +137,81 @@ func (p *Parser) ParseProgram() *ast.Program {
     stmt := p.parseStatement()
     if stmt != nil {
       program.Statements = append(program.Statements, stmt)
+    } else {
+      p.synchronize()
     }
     p.nextToken()
   }
   return program 
 }
 
+/* returns nil when the statement couldn't be parsed, so broken -..
+* statements never end up in the AST.
+* NOTE: typed nil pointers must not leak into the ast.Statement interface. */
 func (p *Parser) parseStatement() ast.Statement {
   switch p.curToken.Type {
     case token.LET:
-      return p.parseLetStatement()
+      if stmt := p.parseLetStatement(); stmt != nil {
+        return stmt
+      }
     case token.RETURN:
-      return p.parseReturnStatement()
+      if stmt := p.parseReturnStatement(); stmt != nil {
+        return stmt
+      }
+    case token.THROW:
+      if stmt := p.parseThrowStatement(); stmt != nil {
+        return stmt
+      }
+    case token.WHILE:
+      if stmt := p.parseWhileStatement(); stmt != nil {
+        return stmt
+      }
+    case token.FOR:
+      if stmt := p.parseForStatement(); stmt != nil {
+        return stmt
+      }
+    case token.BREAK, token.CONTINUE:
+      if stmt := p.parseLoopControlStatement(); stmt != nil {
+        return stmt
+      }
     default:
-      return p.parseExpressionStatement()
+      if stmt := p.parseExpressionStatement(); stmt != nil {
+        return stmt
+      }
+  }
+  return nil
+}
+
+/* panic-mode error recovery: after a broken statement, skip tokens -..
+* until a synchronization point so a single mistake doesn't produce a -..
+* cascade of follow-on errors. Synchronization points are a ';', the -..
+* '}' closing the enclosing block, or the start of the next statement.
+* Braces opened while skipping are skipped as a whole. */
+func (p *Parser) synchronize() {
+  depth := 0
+  for {
+    switch {
+    case p.curTokenIs(token.SEMICOLON) && depth == 0:
+      return
+    case p.curTokenIs(token.LBRACE):
+      depth++
+    case p.curTokenIs(token.RBRACE) && depth > 0:
+      depth--
+    }
+
+    switch p.peekToken.Type {
+    case token.EOF:
+      return
+    case token.RBRACE:
+      if depth == 0 && p.blockDepth > 0 {
+        return
+      }
+    case token.LET, token.RETURN, token.THROW, token.WHILE, token.FOR, token.BREAK, token.CONTINUE:
+      if depth == 0 {
+        return
+      }
+    }
+    p.nextToken()
   }
 }
 
@@ -129,12 +228,15 @@ func (p* Parser) parseLetStatement() *ast.LetStatement {
   }
   p.nextToken()
   stmt.Value = p.parseExpression(LOWEST)
-
-  if _, ok := stmt.Value.(*ast.FunctionLiteral); ok {
-    return stmt
+  if stmt.Value == nil {
+    return nil
+  }
+  // functions remember the name they're bound to, for error messages.
+  if fn, ok := stmt.Value.(*ast.FunctionLiteral); ok {
+    fn.Name = stmt.Name.Value
   }
 
-  for !p.curTokenIs(token.SEMICOLON) {
+  if p.peekTokenIs(token.SEMICOLON) {
     p.nextToken()
   }
   return stmt
@@ -145,6 +247,53 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
   stmt := &ast.ReturnStatement{Token: p.curToken}
   p.nextToken()
   stmt.ReturnValue = p.parseExpression(LOWEST)
+  if stmt.ReturnValue == nil {
+    return nil
+  }
+
+  if p.peekTokenIs(token.SEMICOLON) {
+    p.nextToken()
+  }
+  return stmt
+}
+
+/* throw statement structure - throw <expression> */
+func (p *Parser) parseThrowStatement() *ast.ThrowStatement {
+  stmt := &ast.ThrowStatement{Token: p.curToken}
+  p.nextToken()
+  stmt.Value = p.parseExpression(LOWEST)
+  if stmt.Value == nil {
+    return nil
+  }
+
+  if p.peekTokenIs(token.SEMICOLON) {
+    p.nextToken()
+  }
+  return stmt
+}
+
+/***** Loops parsing *****/
+
+/* while statement structure - while (<expression>) { <statements> } */
+func (p *Parser) parseWhileStatement() *ast.WhileStatement {
+  stmt := &ast.WhileStatement{Token: p.curToken}
+  if !p.expectPeek(token.LPAREN) {
+    return nil
+  }
+  p.nextToken()
+  stmt.Condition = p.parseExpression(LOWEST)
+  if stmt.Condition == nil {
+    return nil
+  }
+  if !p.expectPeek(token.RPAREN) {
+    return nil
+  }
+  if !p.expectPeek(token.LBRACE) {
+    return nil
+  }
+  p.loopDepth++
+  stmt.Body = p.parseBlockStatement()
+  p.loopDepth--
 
   if p.peekTokenIs(token.SEMICOLON) {
     p.nextToken()
@@ -152,9 +301,68 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
   return stmt
 }
 
+/* for statement structure - for (<identifier> in <expression>) { <statements> } */
+func (p *Parser) parseForStatement() *ast.ForStatement {
+  stmt := &ast.ForStatement{Token: p.curToken}
+  if !p.expectPeek(token.LPAREN) {
+    return nil
+  }
+  if !p.expectPeek(token.IDENT) {
+    return nil
+  }
+  stmt.Variable = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+  if !p.expectPeek(token.IN) {
+    return nil
+  }
+  p.nextToken()
+  stmt.Iterable = p.parseExpression(LOWEST)
+  if stmt.Iterable == nil {
+    return nil
+  }
+  if !p.expectPeek(token.RPAREN) {
+    return nil
+  }
+  if !p.expectPeek(token.LBRACE) {
+    return nil
+  }
+  p.loopDepth++
+  stmt.Body = p.parseBlockStatement()
+  p.loopDepth--
+
+  if p.peekTokenIs(token.SEMICOLON) {
+    p.nextToken()
+  }
+  return stmt
+}
+
+// break and continue, only valid inside a loop of the current function.
+func (p *Parser) parseLoopControlStatement() ast.Statement {
+  tok := p.curToken
+  if p.loopDepth == 0 {
+    p.report(Diagnostic{
+      Severity: ERROR,
+      Code:     OUTSIDE_LOOP,
+      Message:  fmt.Sprintf("%s outside of a loop", tok.Literal),
+      Start:    tok.Pos,
+      End:      tokenEnd(tok),
+    })
+    return nil
+  }
+  if p.peekTokenIs(token.SEMICOLON) {
+    p.nextToken()
+  }
+  if tok.Type == token.BREAK {
+    return &ast.BreakStatement{Token: tok}
+  }
+  return &ast.ContinueStatement{Token: tok}
+}
+
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
   stmt := &ast.ExpressionStatement{Token: p.curToken}
   stmt.Expression = p.parseExpression(LOWEST)
+  if stmt.Expression == nil {
+    return nil
+  }
 
   if (p.peekTokenIs(token.SEMICOLON)) {
     p.nextToken()
@@ -162,7 +370,8 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
   return stmt
 }
 
-// this method that kicks off expression parsing
+/* this method that kicks off expression parsing.
+* returns nil if the expression (or any operand of it) is broken. */
 func (p *Parser) parseExpression(precendence int) ast.Expression {
   // prefix is a function that parses the given Token.
   var prefix prefixParseFn = p.prefixParseFns[p.curToken.Type]
@@ -171,7 +380,10 @@ func (p *Parser) parseExpression(precendence int) ast.Expression {
     return nil
   }
   var leftExp ast.Expression = prefix()
-  
+  if leftExp == nil {
+    return nil
+  }
+
   for !p.peekTokenIs(token.SEMICOLON) && precendence < p.peekPrecedence() {
     var infix infixParseFn = p.infixParseFns[p.peekToken.Type]
     if infix == nil {        // no infix expression found
@@ -181,6 +393,9 @@ func (p *Parser) parseExpression(precendence int) ast.Expression {
     p.nextToken()
 
     leftExp = infix(leftExp) // leftExp holds left expression of the infix expression
+    if leftExp == nil {
+      return nil
+    }
   }
 
   return leftExp
@@ -196,6 +411,9 @@ func (p *Parser) parsePrefixExpression() ast.Expression {
 
   // integer parsing.
   expression.Right = p.parseExpression(PREFIX)
+  if expression.Right == nil {
+    return nil
+  }
 
   return expression
 }
@@ -210,6 +428,40 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
   precedence := p.curPrecedence()
   p.nextToken()
   expression.Right = p.parseExpression(precedence)
+  if expression.Right == nil {
+    return nil
+  }
+
+  return expression
+}
+
+/* assignments are right associative, a = b = c assigns c to b and then -..
+* to a, so the value is parsed one precedence level lower. */
+func (p *Parser) parseAssignExpression(target ast.Expression) ast.Expression {
+  expression := &ast.AssignExpression{
+    Token: p.curToken,
+    Operator: p.curToken.Literal,
+    Target: target,
+  }
+  switch target.(type) {
+  case *ast.Identifier, *ast.IndexExpression:
+  default:
+    p.report(Diagnostic{
+      Severity: ERROR,
+      Code:     INVALID_ASSIGNMENT,
+      Message:  fmt.Sprintf("cannot assign to %s", target.String()),
+      Start:    p.curToken.Pos,
+      End:      tokenEnd(p.curToken),
+      Hint:     "only identifiers and index expressions (a[i]) can be assigned to",
+    })
+    return nil
+  }
+  precedence := p.curPrecedence()
+  p.nextToken()
+  expression.Value = p.parseExpression(precedence - 1)
+  if expression.Value == nil {
+    return nil
+  }
 
   return expression
 }
@@ -218,6 +470,9 @@ func (p *Parser) parseGroupedExpression() ast.Expression {
   p.nextToken()
 
   exp := p.parseExpression(LOWEST)
+  if exp == nil {
+    return nil
+  }
 
   if !p.expectPeek(token.RPAREN) {
     return nil
@@ -234,6 +489,9 @@ func (p *Parser) parseIfExpression() ast.Expression {
   }
   p.nextToken()
   expression.Condition = p.parseExpression(LOWEST)
+  if expression.Condition == nil {
+    return nil
+  }
   if !p.expectPeek(token.RPAREN) {
     return nil
   }
@@ -253,6 +511,56 @@ func (p *Parser) parseIfExpression() ast.Expression {
   return expression
 }
 
+/***** try catch finally parsing *****/
+
+func (p *Parser) parseTryExpression() ast.Expression {
+  expression := &ast.TryExpression{Token: p.curToken}
+  if !p.expectPeek(token.LBRACE) {
+    return nil
+  }
+  expression.Block = p.parseBlockStatement()
+
+  if p.peekTokenIs(token.CATCH) {
+    p.nextToken()
+    // the parameter is optional, catch { ... } ignores the error.
+    if p.peekTokenIs(token.LPAREN) {
+      p.nextToken()
+      if !p.expectPeek(token.IDENT) {
+        return nil
+      }
+      expression.Param = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+      if !p.expectPeek(token.RPAREN) {
+        return nil
+      }
+    }
+    if !p.expectPeek(token.LBRACE) {
+      return nil
+    }
+    expression.Catch = p.parseBlockStatement()
+  }
+
+  if p.peekTokenIs(token.FINALLY) {
+    p.nextToken()
+    if !p.expectPeek(token.LBRACE) {
+      return nil
+    }
+    expression.Finally = p.parseBlockStatement()
+  }
+
+  if expression.Catch == nil && expression.Finally == nil {
+    p.report(Diagnostic{
+      Severity: ERROR,
+      Code:     TRY_WITHOUT_HANDLER,
+      Message:  "try without catch or finally",
+      Start:    expression.Token.Pos,
+      End:      tokenEnd(expression.Token),
+      Hint:     "add a `catch (e) { ... }` or a `finally { ... }` block",
+    })
+    return nil
+  }
+  return expression
+}
+
 /***** Functions parsing *****/
 
 func (p *Parser) parseFunctionLiteral() ast.Expression {
@@ -260,76 +568,204 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
   if !p.expectPeek(token.LPAREN) {
     return nil
   }
-  lit.Parameters = p.parseFunctionParameters()
+  if !p.parseFunctionParameters(lit) {
+    return nil
+  }
   if !p.expectPeek(token.LBRACE) {
     return nil
   }
+  // a loop around the function literal doesn't enclose its body.
+  loopDepth := p.loopDepth
+  p.loopDepth = 0
   lit.Body = p.parseBlockStatement()
+  p.loopDepth = loopDepth
     return lit
   }
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
-  identifiers := []*ast.Identifier{}
+/* (<ident>, <ident> = <default>, ...<ident>) - fills lit.Parameters, -..
+* lit.Defaults and lit.Rest, optional parameters must come after the -..
+* required ones and the rest parameter must be the last one. */
+func (p *Parser) parseFunctionParameters(lit *ast.FunctionLiteral) bool {
+  lit.Parameters = []*ast.Identifier{}
+  lit.Defaults = []ast.Expression{}
 
   if p.peekTokenIs(token.RPAREN) {
     p.nextToken()
-    return identifiers
+    return true
+  }
+  if !p.parseFunctionParameter(lit) {
+    return false
   }
-  p.nextToken()
-
-  ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-  identifiers = append(identifiers, ident)
 
-  for p.peekTokenIs(token.COMMA) {
-    p.nextToken()
+  for p.peekTokenIs(token.COMMA) && lit.Rest == nil {
     p.nextToken()
+    if !p.parseFunctionParameter(lit) {
+      return false
+    }
+  }
+
+  return p.expectPeek(token.RPAREN)
+}
 
-    ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-    identifiers = append(identifiers, ident)
+func (p *Parser) parseFunctionParameter(lit *ast.FunctionLiteral) bool {
+  if p.peekTokenIs(token.ELLIPSIS) {
+    p.nextToken()
+    if !p.expectPeek(token.IDENT) {
+      return false
+    }
+    lit.Rest = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+    return true
+  }
+  if !p.expectPeek(token.IDENT) {
+    return false
   }
+  ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 
-  if !p.expectPeek(token.RPAREN) {
-    return nil
+  var defaultValue ast.Expression
+  if p.peekTokenIs(token.ASSIGN) {
+    p.nextToken()
+    p.nextToken()
+    if defaultValue = p.parseExpression(LOWEST); defaultValue == nil {
+      return false
+    }
+  } else if lit.Required() < len(lit.Parameters) {
+    p.report(Diagnostic{
+      Severity: ERROR,
+      Code:     REQUIRED_AFTER_OPTIONAL,
+      Message:  fmt.Sprintf("required parameter %s follows an optional parameter", ident.Value),
+      Start:    ident.Token.Pos,
+      End:      tokenEnd(ident.Token),
+      Hint:     fmt.Sprintf("give %s a default value or move it before the optional parameters", ident.Value),
+    })
+    return false
   }
-  return identifiers
+  lit.Parameters = append(lit.Parameters, ident)
+  lit.Defaults = append(lit.Defaults, defaultValue)
+  return true
 }
 
 // Function calls expressions
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
   exp := &ast.CallExpression{Token: p.curToken, Function: function}
-  exp.Arguments = p.parseCallArguments()
+  exp.Arguments = p.parseExpressionList(token.RPAREN)
+  if exp.Arguments == nil {
+    return nil
+  }
   return exp
   }
 
-func (p *Parser) parseCallArguments() []ast.Expression {
+/* parses comma separated expressions up to the #end token, used for -..
+* call arguments and array elements, both accept ...spread elements. */
+func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
   args := []ast.Expression{}
-  if p.peekTokenIs(token.RPAREN) {
+  if p.peekTokenIs(end) {
     p.nextToken()
     return args
   }
   p.nextToken()
-  args = append(args, p.parseExpression(LOWEST))
+  arg := p.parseListElement()
+  if arg == nil {
+    return nil
+  }
+  args = append(args, arg)
   for p.peekTokenIs(token.COMMA) {
     p.nextToken()
     p.nextToken()
-    args = append(args, p.parseExpression(LOWEST))
+    arg := p.parseListElement()
+    if arg == nil {
+      return nil
+    }
+    args = append(args, arg)
   }
-  if !p.expectPeek(token.RPAREN) {
+  if !p.expectPeek(end) {
     return nil
   }
   return args
 }
 
+func (p *Parser) parseListElement() ast.Expression {
+  if !p.curTokenIs(token.ELLIPSIS) {
+    return p.parseExpression(LOWEST)
+  }
+  spread := &ast.SpreadExpression{Token: p.curToken}
+  p.nextToken()
+  if spread.Value = p.parseExpression(LOWEST); spread.Value == nil {
+    return nil
+  }
+  return spread
+}
+
+/***** Arrays parsing *****/
+
+func (p *Parser) parseArrayLiteral() ast.Expression {
+  array := &ast.ArrayLiteral{Token: p.curToken}
+  array.Elements = p.parseExpressionList(token.RBRACKET)
+  if array.Elements == nil {
+    return nil
+  }
+  return array
+}
+
+func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+  exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+  p.nextToken()
+  exp.Index = p.parseExpression(LOWEST)
+  if exp.Index == nil {
+    return nil
+  }
+  if !p.expectPeek(token.RBRACKET) {
+    return nil
+  }
+  return exp
+}
+
+/***** Hashes parsing *****/
+
+// {<key>: <value>, ...}
+func (p *Parser) parseHashLiteral() ast.Expression {
+  hash := &ast.HashLiteral{Token: p.curToken, Pairs: []ast.HashLiteralPair{}}
+
+  for !p.peekTokenIs(token.RBRACE) {
+    p.nextToken()
+    key := p.parseExpression(LOWEST)
+    if key == nil {
+      return nil
+    }
+    if !p.expectPeek(token.COLON) {
+      return nil
+    }
+    p.nextToken()
+    value := p.parseExpression(LOWEST)
+    if value == nil {
+      return nil
+    }
+    hash.Pairs = append(hash.Pairs, ast.HashLiteralPair{Key: key, Value: value})
+
+    if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+      return nil
+    }
+  }
+  if !p.expectPeek(token.RBRACE) {
+    return nil
+  }
+  return hash
+}
+
 /***** if-else and functions body are represented as BlockStatement  ******/
 
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
   block := &ast.BlockStatement{Token: p.curToken}
   block.Statements = []ast.Statement{}
+  p.blockDepth++
+  defer func() { p.blockDepth-- }()
+
   p.nextToken()
   for !p.curTokenIs(token.RBRACE) && !p.curTokenIs(token.EOF) {
     stmt := p.parseStatement()
     if stmt != nil {
       block.Statements = append(block.Statements, stmt)
+    } else {
+      p.synchronize()
     }
     p.nextToken()
   }
@@ -341,6 +777,36 @@ func (p *Parser) parseStringLiteral() ast.Expression {
   return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
 }
 
+// reports the ILLEGAL token, there is never an expression to return.
+func (p *Parser) parseIllegal() ast.Expression {
+  p.illegalTokenError(p.curToken)
+  return nil
+}
+
+// TEMPLATE_HEAD <expression> (TEMPLATE_MIDDLE <expression>)* TEMPLATE_TAIL
+func (p *Parser) parseInterpolatedString() ast.Expression {
+  str := &ast.InterpolatedString{Token: p.curToken}
+  str.Parts = []ast.Expression{&ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}}
+  for {
+    p.nextToken()
+    exp := p.parseExpression(LOWEST)
+    if exp == nil {
+      return nil
+    }
+    str.Parts = append(str.Parts, exp)
+
+    if p.peekTokenIs(token.TEMPLATE_MIDDLE) {
+      p.nextToken()
+    } else if !p.expectPeek(token.TEMPLATE_TAIL) {
+      return nil
+    }
+    str.Parts = append(str.Parts, &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal})
+    if p.curTokenIs(token.TEMPLATE_TAIL) {
+      return str
+    }
+  }
+}
+
 func (p *Parser) parseIdentifier() ast.Expression {
   return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 }
@@ -352,9 +818,38 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
   lit := &ast.IntegerLiteral{Token: p.curToken}
 
   value, error := strconv.ParseInt(p.curToken.Literal, 0, 64)
+  if numError, ok := error.(*strconv.NumError); ok && numError.Err == strconv.ErrRange {
+    if lit.Big, ok = new(big.Int).SetString(p.curToken.Literal, 0); ok {
+      return lit
+    }
+  }
+  if error != nil {
+    p.report(Diagnostic{
+      Severity: ERROR,
+      Code:     INVALID_INTEGER,
+      Message:  fmt.Sprintf("count not parse %q as integer", p.curToken.Literal),
+      Start:    p.curToken.Pos,
+      End:      tokenEnd(p.curToken),
+    })
+    return nil
+  }
+  lit.Value = value
+
+  return lit
+}
+
+func (p *Parser) parseFloatLiteral() ast.Expression {
+  lit := &ast.FloatLiteral{Token: p.curToken}
+
+  value, error := strconv.ParseFloat(p.curToken.Literal, 64)
   if error != nil {
-    msg := fmt.Sprintf("count not parse %q as integer", p.curToken.Literal)
-    p.errors = append(p.errors, msg)
+    p.report(Diagnostic{
+      Severity: ERROR,
+      Code:     INVALID_FLOAT,
+      Message:  fmt.Sprintf("could not parse %q as float", p.curToken.Literal),
+      Start:    p.curToken.Pos,
+      End:      tokenEnd(p.curToken),
+    })
     return nil
   }
   lit.Value = value
@@ -414,18 +909,14 @@ func (p *Parser) registerInfix(tokenType token.TokenType, fn infixParseFn) {
 }
 
 /***** Error Handling *****/
-func (p *Parser) Errors() []string {
-  return p.errors
-}
 
-func (p* Parser) peekError(t token.TokenType) {
-  msg := fmt.Sprintf("expected next token to be %s, got %s instead",t, p.peekToken.Type)
-  p.errors = append(p.errors, msg)
-}
-
-func (p *Parser) noPrefixParseFnError(t token.TokenType) {
-  msg := fmt.Sprintf("no prefix parse function for %s found", t)
-  p.errors = append(p.errors, msg)
+// Errors returns the error diagnostics formatted as "line:column: message".
+func (p *Parser) Errors() []string {
+  errors := []string{}
+  for _, d := range p.diagnostics {
+    if d.Severity == ERROR {
+      errors = append(errors, d.String())
+    }
+  }
+  return errors
 }
-
-
@@ -0,0 +1,70 @@
+package parser
+
+import (
+  "testing"
+  "Monkey/ast"
+  "Monkey/lexer"
+)
+
+func TestParsingArrayLiterals(t *testing.T) {
+  input := "[1, 2 * 2, 3 + 3]"
+  l := lexer.New(input)
+  p := New(l)
+  program := p.ParseProgram()
+  checkParserErrors(t, p)
+
+  stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+  if !ok {
+    t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+    program.Statements[0])
+  }
+  array, ok := stmt.Expression.(*ast.ArrayLiteral)
+  if !ok {
+    t.Fatalf("exp not ast.ArrayLiteral. got=%T", stmt.Expression)
+  }
+  if len(array.Elements) != 3 {
+    t.Fatalf("len(array.Elements) not 3. got=%d", len(array.Elements))
+  }
+  testIntegerLiteral(t, array.Elements[0], 1)
+  testInfixExpression(t, array.Elements[1], 2, "*", 2)
+  testInfixExpression(t, array.Elements[2], 3, "+", 3)
+}
+
+func TestParsingEmptyArrayLiterals(t *testing.T) {
+  input := "[]"
+  l := lexer.New(input)
+  p := New(l)
+  program := p.ParseProgram()
+  checkParserErrors(t, p)
+
+  stmt := program.Statements[0].(*ast.ExpressionStatement)
+  array, ok := stmt.Expression.(*ast.ArrayLiteral)
+  if !ok {
+    t.Fatalf("exp not ast.ArrayLiteral. got=%T", stmt.Expression)
+  }
+  if len(array.Elements) != 0 {
+    t.Fatalf("len(array.Elements) not 0. got=%d", len(array.Elements))
+  }
+}
+
+func TestParsingIndexExpressions(t *testing.T) {
+  input := "myArray[1 + 1]"
+  l := lexer.New(input)
+  p := New(l)
+  program := p.ParseProgram()
+  checkParserErrors(t, p)
+
+  stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+  if !ok {
+    t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+    program.Statements[0])
+  }
+  indexExp, ok := stmt.Expression.(*ast.IndexExpression)
+  if !ok {
+    t.Fatalf("exp not *ast.IndexExpression. got=%T", stmt.Expression)
+  }
+  if !testIdentifier(t, indexExp.Left, "myArray") {
+    return
+  }
+  testInfixExpression(t, indexExp.Index, 1, "+", 1)
+}
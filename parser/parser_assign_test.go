@@ -0,0 +1,64 @@
+package parser
+
+import (
+  "testing"
+  "Monkey/lexer"
+  "Monkey/ast"
+)
+
+func TestAssignExpression(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected string
+  }{
+    {"x = 5;", "(x = 5)"},
+    {"x += y * 2;", "(x += (y * 2))"},
+    {"a = b = c;", "(a = (b = c))"},
+    {"a[i] -= 1;", "((a[i]) -= 1)"},
+    {"h[\"k\"][0] /= 2;", "(((h[k])[0]) /= 2)"},
+    {"x *= f(1) + 2;", "(x *= (f(1) + 2))"},
+  }
+
+  for _, tt := range tests {
+    p := New(lexer.New(tt.input))
+    program := p.ParseProgram()
+    checkParserErrors(t, p)
+    if len(program.Statements) != 1 {
+      t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+    }
+    stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+    if !ok {
+      t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+    }
+    if _, ok := stmt.Expression.(*ast.AssignExpression); !ok {
+      t.Fatalf("stmt.Expression is not ast.AssignExpression. got=%T", stmt.Expression)
+    }
+    if stmt.String() != tt.expected {
+      t.Errorf("stmt.String() wrong. expected=%q, got=%q", tt.expected, stmt.String())
+    }
+  }
+}
+
+func TestInvalidAssignmentTarget(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected string
+  }{
+    {"5 = x;", "1:3: cannot assign to 5"},
+    {"f() += 1;", "1:5: cannot assign to f()"},
+    {"a + b = c;", "1:7: cannot assign to (a + b)"},
+  }
+
+  for _, tt := range tests {
+    p := New(lexer.New(tt.input))
+    p.ParseProgram()
+    errors := p.Errors()
+    if len(errors) != 1 || errors[0] != tt.expected {
+      t.Errorf("wrong errors for %q. expected=%q, got=%q", tt.input, tt.expected, errors)
+      continue
+    }
+    if p.Diagnostics()[0].Code != INVALID_ASSIGNMENT {
+      t.Errorf("wrong diagnostic code. got=%q", p.Diagnostics()[0].Code)
+    }
+  }
+}
@@ -0,0 +1,97 @@
+package parser
+
+import (
+  "strings"
+  "testing"
+  "Monkey/lexer"
+  "Monkey/token"
+)
+
+func TestDiagnostics(t *testing.T) {
+  input := "let x 5;"
+  p := New(lexer.New(input))
+  p.ParseProgram()
+
+  diagnostics := p.Diagnostics()
+  if len(diagnostics) == 0 {
+    t.Fatalf("no diagnostics reported")
+  }
+  d := diagnostics[0]
+  if d.Severity != ERROR || d.Code != UNEXPECTED_TOKEN {
+    t.Errorf("wrong severity/code. got=%s/%s", d.Severity, d.Code)
+  }
+  if d.Expected != token.ASSIGN || d.Actual != token.INT {
+    t.Errorf("wrong expected/actual. got=%s/%s", d.Expected, d.Actual)
+  }
+  if d.Start.Column != 7 || d.End.Column != 8 {
+    t.Errorf("wrong span. got=%d-%d", d.Start.Column, d.End.Column)
+  }
+  if d.Hint == "" {
+    t.Errorf("expected a fix hint")
+  }
+}
+
+func TestDiagnosticRender(t *testing.T) {
+  input := "let a = 1;\nlet b = add(a, 2;"
+  p := New(lexer.NewFile("main.monkey", input))
+  p.ParseProgram()
+
+  diagnostics := p.Diagnostics()
+  if len(diagnostics) == 0 {
+    t.Fatalf("no diagnostics reported")
+  }
+  expected := "main.monkey:2:17: error[unexpected-token]: expected next token to be ), got ; instead\n" +
+  "  |\n" +
+  "2 | let b = add(a, 2;\n" +
+  "  |                 ^\n" +
+  "  = hint: insert the missing `)`\n"
+
+  if rendered := diagnostics[0].Render(input); rendered != expected {
+    t.Errorf("wrong rendering.\nexpected=\n%s\ngot=\n%s", expected, rendered)
+  }
+}
+
+// carets line up under the offending character after multi-byte ones.
+func TestDiagnosticRenderUnicode(t *testing.T) {
+  input := "let größe = (1;"
+  p := New(lexer.New(input))
+  p.ParseProgram()
+
+  diagnostics := p.Diagnostics()
+  if len(diagnostics) == 0 {
+    t.Fatalf("no diagnostics reported")
+  }
+  expected := "  |\n" +
+  "1 | let größe = (1;\n" +
+  "  |               ^\n"
+
+  if rendered := diagnostics[0].Render(input); !strings.Contains(rendered, expected) {
+    t.Errorf("wrong rendering.\nexpected=\n%s\ngot=\n%s", expected, rendered)
+  }
+}
+
+func TestIllegalTokenDiagnostics(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected string
+  }{
+    {"let s = \"abc\nlet t = 1;", "1:9: unterminated string literal"},
+    {`puts("a\qb")`, "1:6: invalid escape sequence \\q"},
+    {"let x = 1 @ 2;", "1:11: illegal character \"@\""},
+    {"/* open", "1:1: unterminated block comment"},
+    {"a & b", "1:3: illegal character \"&\""},
+  }
+
+  for _, tt := range tests {
+    p := New(lexer.New(tt.input))
+    p.ParseProgram()
+    errors := p.Errors()
+    if len(errors) != 1 || errors[0] != tt.expected {
+      t.Errorf("wrong errors for %q. expected=%q, got=%q", tt.input, tt.expected, errors)
+      continue
+    }
+    if p.Diagnostics()[0].Code != ILLEGAL_TOKEN {
+      t.Errorf("wrong diagnostic code. got=%q", p.Diagnostics()[0].Code)
+    }
+  }
+}
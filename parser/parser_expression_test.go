@@ -71,6 +71,49 @@ func TestIntegerLiteralExpression(t *testing.T) {
   }
 }
 
+func TestBigIntegerLiteralExpression(t *testing.T) {
+  input := "123456789012345678901234567890;"
+  l := lexer.New(input)
+  p := New(l)
+  program := p.ParseProgram()
+  checkParserErrors(t, p)
+
+  stmt := program.Statements[0].(*ast.ExpressionStatement)
+  literal, ok := stmt.Expression.(*ast.IntegerLiteral)
+  if !ok {
+    t.Fatalf("exp not *ast.IntegerLiteral. got=%T", stmt.Expression)
+  }
+  if literal.Big == nil || literal.Big.String() != "123456789012345678901234567890" {
+    t.Errorf("literal.Big wrong. got=%v", literal.Big)
+  }
+}
+
+func TestFloatLiteralExpression(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected float64
+  }{
+    {"3.14;", 3.14},
+    {"1e-9;", 1e-9},
+    {"2.5E+3;", 2500},
+  }
+  for _, tt := range tests {
+    l := lexer.New(tt.input)
+    p := New(l)
+    program := p.ParseProgram()
+    checkParserErrors(t, p)
+
+    stmt := program.Statements[0].(*ast.ExpressionStatement)
+    literal, ok := stmt.Expression.(*ast.FloatLiteral)
+    if !ok {
+      t.Fatalf("exp not *ast.FloatLiteral. got=%T", stmt.Expression)
+    }
+    if literal.Value != tt.expected {
+      t.Errorf("literal.Value not %g. got=%g", tt.expected, literal.Value)
+    }
+  }
+}
+
 func testIntegerLiteral(t *testing.T, il ast.Expression, value int64) bool {
   integ, ok := il.(*ast.IntegerLiteral)
   if !ok {
@@ -168,6 +211,10 @@ func TestParsingInfixExpressions(t *testing.T) {
     {"5 < 5;", 5, "<", 5},
     {"5 == 5;", 5, "==", 5},
     {"5 != 5;", 5, "!=", 5},
+    {"5 >= 5;", 5, ">=", 5},
+    {"5 <= 5;", 5, "<=", 5},
+    {"true && false", true, "&&", false},
+    {"false || true", false, "||", true},
     {"true == true", true, "==", true},
     {"true != false", true, "!=", false},
     {"false == false", false, "==", false},
@@ -111,3 +111,100 @@ func TestCallExpressionParsing(t *testing.T) {
   testInfixExpression(t, exp.Arguments[1], 2, "*", 3)
   testInfixExpression(t, exp.Arguments[2], 4, "+", 5)
 }
+
+func TestFunctionDefaultParameters(t *testing.T) {
+  input := `let greet = fn(name, greeting = "hi", times = 1 + 1) { greeting };`
+  l := lexer.New(input)
+  p := New(l)
+  program := p.ParseProgram()
+  checkParserErrors(t, p)
+
+  stmt := program.Statements[0].(*ast.LetStatement)
+  function, ok := stmt.Value.(*ast.FunctionLiteral)
+  if !ok {
+    t.Fatalf("stmt.Value is not ast.FunctionLiteral. got=%T", stmt.Value)
+  }
+  if function.Name != "greet" {
+    t.Errorf("function.Name not %q. got=%q", "greet", function.Name)
+  }
+  if len(function.Parameters) != 3 || len(function.Defaults) != 3 {
+    t.Fatalf("wrong number of parameters/defaults. got=%d/%d",
+    len(function.Parameters), len(function.Defaults))
+  }
+  if function.Defaults[0] != nil {
+    t.Errorf("first parameter should be required. got=%s", function.Defaults[0])
+  }
+  if function.Defaults[1].String() != "hi" {
+    t.Errorf("wrong default. got=%s", function.Defaults[1])
+  }
+  testInfixExpression(t, function.Defaults[2], 1, "+", 1)
+  if function.Required() != 1 {
+    t.Errorf("function.Required() not 1. got=%d", function.Required())
+  }
+  expected := `let greet = fn(name, greeting = hi, times = (1 + 1)) greeting;`
+  if program.String() != expected {
+    t.Errorf("program.String() wrong. want=%q, got=%q", expected, program.String())
+  }
+}
+
+func TestRequiredParameterAfterOptional(t *testing.T) {
+  p := New(lexer.New("fn(a = 1, b) { b }"))
+  p.ParseProgram()
+
+  diagnostics := p.Diagnostics()
+  if len(diagnostics) != 1 {
+    t.Fatalf("expected 1 diagnostic. got=%d (%q)", len(diagnostics), p.Errors())
+  }
+  if diagnostics[0].Code != REQUIRED_AFTER_OPTIONAL {
+    t.Errorf("wrong diagnostic code. got=%s", diagnostics[0].Code)
+  }
+}
+
+func TestVariadicFunctionParsing(t *testing.T) {
+  tests := []struct {
+    input        string
+    expectedRest string
+    expected     string
+  }{
+    {"fn(first, ...rest) { rest }", "rest", "fn(first, ...rest) rest"},
+    {"fn(...all) { all }", "all", "fn(...all) all"},
+    {"fn(a, b = 1, ...more) { a }", "more", "fn(a, b = 1, ...more) a"},
+  }
+  for _, tt := range tests {
+    l := lexer.New(tt.input)
+    p := New(l)
+    program := p.ParseProgram()
+    checkParserErrors(t, p)
+
+    stmt := program.Statements[0].(*ast.ExpressionStatement)
+    function := stmt.Expression.(*ast.FunctionLiteral)
+    if function.Rest == nil || function.Rest.Value != tt.expectedRest {
+      t.Errorf("function.Rest wrong. want=%q, got=%v", tt.expectedRest, function.Rest)
+    }
+    if function.String() != tt.expected {
+      t.Errorf("function.String() wrong. want=%q, got=%q", tt.expected, function.String())
+    }
+  }
+}
+
+func TestSpreadArgumentsParsing(t *testing.T) {
+  input := "log(level, ...messages, [...a, 1])"
+  l := lexer.New(input)
+  p := New(l)
+  program := p.ParseProgram()
+  checkParserErrors(t, p)
+
+  stmt := program.Statements[0].(*ast.ExpressionStatement)
+  exp := stmt.Expression.(*ast.CallExpression)
+  if len(exp.Arguments) != 3 {
+    t.Fatalf("wrong length of arguments. got=%d", len(exp.Arguments))
+  }
+  spread, ok := exp.Arguments[1].(*ast.SpreadExpression)
+  if !ok {
+    t.Fatalf("argument is not ast.SpreadExpression. got=%T", exp.Arguments[1])
+  }
+  testIdentifier(t, spread.Value, "messages")
+  if exp.String() != "log(level, ...messages, [...a, 1])" {
+    t.Errorf("exp.String() wrong. got=%q", exp.String())
+  }
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+  "testing"
+  "Monkey/ast"
+  "Monkey/lexer"
+)
+
+func TestParsingHashLiteralsStringKeys(t *testing.T) {
+  input := `{"one": 1, "two": 2, "three": 3}`
+  l := lexer.New(input)
+  p := New(l)
+  program := p.ParseProgram()
+  checkParserErrors(t, p)
+
+  stmt := program.Statements[0].(*ast.ExpressionStatement)
+  hash, ok := stmt.Expression.(*ast.HashLiteral)
+  if !ok {
+    t.Fatalf("exp is not ast.HashLiteral. got=%T", stmt.Expression)
+  }
+  expected := []struct {
+    key   string
+    value int64
+  }{
+    {"one", 1},
+    {"two", 2},
+    {"three", 3},
+  }
+  if len(hash.Pairs) != len(expected) {
+    t.Fatalf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
+  }
+  for i, pair := range hash.Pairs {
+    literal, ok := pair.Key.(*ast.StringLiteral)
+    if !ok {
+      t.Errorf("key is not ast.StringLiteral. got=%T", pair.Key)
+      continue
+    }
+    if literal.Value != expected[i].key {
+      t.Errorf("key %d wrong. want=%q, got=%q", i, expected[i].key, literal.Value)
+    }
+    testIntegerLiteral(t, pair.Value, expected[i].value)
+  }
+}
+
+func TestParsingEmptyHashLiteral(t *testing.T) {
+  input := "{}"
+  l := lexer.New(input)
+  p := New(l)
+  program := p.ParseProgram()
+  checkParserErrors(t, p)
+
+  stmt := program.Statements[0].(*ast.ExpressionStatement)
+  hash, ok := stmt.Expression.(*ast.HashLiteral)
+  if !ok {
+    t.Fatalf("exp is not ast.HashLiteral. got=%T", stmt.Expression)
+  }
+  if len(hash.Pairs) != 0 {
+    t.Errorf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
+  }
+}
+
+func TestParsingHashLiteralsWithExpressions(t *testing.T) {
+  input := `{"one": 0 + 1, true: 10 - 8, 3: 15 / 5}`
+  l := lexer.New(input)
+  p := New(l)
+  program := p.ParseProgram()
+  checkParserErrors(t, p)
+
+  stmt := program.Statements[0].(*ast.ExpressionStatement)
+  hash, ok := stmt.Expression.(*ast.HashLiteral)
+  if !ok {
+    t.Fatalf("exp is not ast.HashLiteral. got=%T", stmt.Expression)
+  }
+  if len(hash.Pairs) != 3 {
+    t.Fatalf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
+  }
+  testBooleanLiteral(t, hash.Pairs[1].Key, true)
+  testIntegerLiteral(t, hash.Pairs[2].Key, 3)
+  testInfixExpression(t, hash.Pairs[0].Value, 0, "+", 1)
+  testInfixExpression(t, hash.Pairs[1].Value, 10, "-", 8)
+  testInfixExpression(t, hash.Pairs[2].Value, 15, "/", 5)
+}
@@ -0,0 +1,85 @@
+package parser
+
+import (
+  "testing"
+  "Monkey/lexer"
+  "Monkey/ast"
+)
+
+func TestWhileStatement(t *testing.T) {
+  input := `while (x < y) { if (x == 1) { break } continue; x }`
+  p := New(lexer.New(input))
+  program := p.ParseProgram()
+  checkParserErrors(t, p)
+  if len(program.Statements) != 1 {
+    t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+  }
+
+  stmt, ok := program.Statements[0].(*ast.WhileStatement)
+  if !ok {
+    t.Fatalf("program.Statements[0] is not ast.WhileStatement. got=%T", program.Statements[0])
+  }
+  if !testInfixExpression(t, stmt.Condition, "x", "<", "y") {
+    return
+  }
+  if len(stmt.Body.Statements) != 3 {
+    t.Fatalf("body is not 3 statements. got=%d", len(stmt.Body.Statements))
+  }
+  if _, ok := stmt.Body.Statements[1].(*ast.ContinueStatement); !ok {
+    t.Errorf("body.Statements[1] is not ast.ContinueStatement. got=%T", stmt.Body.Statements[1])
+  }
+  if stmt.String() != "while(x < y) if(x == 1) break;continue;x" {
+    t.Errorf("stmt.String() wrong. got=%q", stmt.String())
+  }
+}
+
+func TestForStatement(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected string
+  }{
+    {"for (x in xs) { x }", "for(x in xs) x"},
+    {"for (i in 0..n + 1) { break }", "for(i in (0 .. (n + 1))) break;"},
+    {"for (i in 0..<len(xs)) { continue }", "for(i in (0 ..< len(xs))) continue;"},
+  }
+
+  for _, tt := range tests {
+    p := New(lexer.New(tt.input))
+    program := p.ParseProgram()
+    checkParserErrors(t, p)
+    if len(program.Statements) != 1 {
+      t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+    }
+    stmt, ok := program.Statements[0].(*ast.ForStatement)
+    if !ok {
+      t.Fatalf("program.Statements[0] is not ast.ForStatement. got=%T", program.Statements[0])
+    }
+    if stmt.String() != tt.expected {
+      t.Errorf("stmt.String() wrong. expected=%q, got=%q", tt.expected, stmt.String())
+    }
+  }
+}
+
+func TestLoopControlOutsideLoop(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected string
+  }{
+    {"break;", "1:1: break outside of a loop"},
+    {"if (x) { continue }", "1:10: continue outside of a loop"},
+    {"while (x) { let f = fn() { break }; }", "1:28: break outside of a loop"},
+  }
+
+  for _, tt := range tests {
+    p := New(lexer.New(tt.input))
+    p.ParseProgram()
+    errors := p.Errors()
+    if len(errors) != 1 || errors[0] != tt.expected {
+      t.Errorf("wrong errors for %q. expected=%q, got=%q", tt.input, tt.expected, errors)
+      continue
+    }
+    if p.Diagnostics()[0].Code != OUTSIDE_LOOP {
+      t.Errorf("wrong diagnostic code. got=%q", p.Diagnostics()[0].Code)
+    }
+  }
+}
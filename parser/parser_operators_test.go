@@ -106,6 +106,34 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
       "3 + 4 * 5 == 3 * 1 + 4 * 5",
       "((3 + (4 * 5)) == ((3 * 1) + (4 * 5)))",
     },
+    {
+      "a * [1, 2, 3, 4][b * c] * d",
+      "((a * ([1, 2, 3, 4][(b * c)])) * d)",
+    },
+    {
+      "add(a * b[2], b[1], 2 * [1, 2][1])",
+      "add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))",
+    },
+    {
+      "a >= 0 && a <= 10",
+      "((a >= 0) && (a <= 10))",
+    },
+    {
+      "a || b && c",
+      "(a || (b && c))",
+    },
+    {
+      "a && b || c && d",
+      "((a && b) || (c && d))",
+    },
+    {
+      "!a || b == c",
+      "((!a) || (b == c))",
+    },
+    {
+      "x = a || b",
+      "(x = (a || b))",
+    },
   }
   for _, tt := range tests {
     l := lexer.New(tt.input)
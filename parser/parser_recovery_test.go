@@ -0,0 +1,144 @@
+package parser
+
+import (
+  "testing"
+  "Monkey/ast"
+  "Monkey/lexer"
+)
+
+func TestErrorRecovery(t *testing.T) {
+  tests := []struct {
+    input              string
+    expectedErrors     int
+    expectedStatements []string
+  }{
+    {"let x 5; let y = 10; y;", 1, []string{"let y = 10;", "y"}},
+    {"let = 5 + ; let y = 1;", 1, []string{"let y = 1;"}},
+    {"let x = 5 +; y", 1, []string{"y"}},
+    {"add(1, 2; let z = 3;", 1, []string{"let z = 3;"}},
+    {"if (x { 1 } let z = 3;", 1, []string{"let z = 3;"}},
+    {"let f = fn(a) { let = 1; a }; f(2);", 1, []string{"let f = fn(a) a;", "f(2)"}},
+    {"fn(1, x) { x }; 5", 1, []string{"5"}},
+    {"fn(a = , b) { x }; 5", 1, []string{"5"}},
+    {"fn(...a, b) { x }; 5", 1, []string{"5"}},
+    {"let a = ...b; 5", 1, []string{"5"}},
+    {"} 4; 5", 1, []string{"5"}},
+    {"let a = [1, 2; a[0", 2, []string{}},
+    {`let h = {"a" 1}; let b = {"a": 1};`, 1, []string{`let b = {a: 1};`}},
+  }
+  for _, tt := range tests {
+    p := New(lexer.New(tt.input))
+    program := p.ParseProgram()
+
+    if len(p.Errors()) != tt.expectedErrors {
+      t.Errorf("%q: wrong number of errors. want=%d, got=%d (%q)",
+      tt.input, tt.expectedErrors, len(p.Errors()), p.Errors())
+    }
+    if len(program.Statements) != len(tt.expectedStatements) {
+      t.Errorf("%q: wrong number of statements. want=%d, got=%d (%q)",
+      tt.input, len(tt.expectedStatements), len(program.Statements), program.String())
+      continue
+    }
+    for i, stmt := range program.Statements {
+      if stmt.String() != tt.expectedStatements[i] {
+        t.Errorf("%q: statement %d wrong. want=%q, got=%q",
+        tt.input, i, tt.expectedStatements[i], stmt.String())
+      }
+    }
+    testNoNilNodes(t, program)
+  }
+}
+
+// walks the AST and fails on nil statements/expressions.
+func testNoNilNodes(t *testing.T, node ast.Node) {
+  var walk func(node ast.Node)
+  walk = func(node ast.Node) {
+    switch node := node.(type) {
+    case nil:
+      t.Fatalf("AST contains a nil node")
+    case *ast.Program:
+      for _, s := range node.Statements {
+        walk(s)
+      }
+    case *ast.BlockStatement:
+      for _, s := range node.Statements {
+        walk(s)
+      }
+    case *ast.LetStatement:
+      walk(node.Name)
+      walk(node.Value)
+    case *ast.ReturnStatement:
+      walk(node.ReturnValue)
+    case *ast.ExpressionStatement:
+      walk(node.Expression)
+    case *ast.PrefixExpression:
+      walk(node.Right)
+    case *ast.InfixExpression:
+      walk(node.Left)
+      walk(node.Right)
+    case *ast.IfExpression:
+      walk(node.Condition)
+      walk(node.Consequence)
+      if node.Alternative != nil {
+        walk(node.Alternative)
+      }
+    case *ast.FunctionLiteral:
+      for _, param := range node.Parameters {
+        walk(param)
+      }
+      for _, d := range node.Defaults {
+        if d != nil { // nil marks a required parameter
+          walk(d)
+        }
+      }
+      walk(node.Body)
+    case *ast.CallExpression:
+      walk(node.Function)
+      for _, arg := range node.Arguments {
+        walk(arg)
+      }
+    case *ast.ArrayLiteral:
+      for _, el := range node.Elements {
+        walk(el)
+      }
+    case *ast.IndexExpression:
+      walk(node.Left)
+      walk(node.Index)
+    case *ast.SpreadExpression:
+      walk(node.Value)
+    case *ast.ThrowStatement:
+      walk(node.Value)
+    case *ast.InterpolatedString:
+      for _, part := range node.Parts {
+        walk(part)
+      }
+    case *ast.AssignExpression:
+      walk(node.Target)
+      walk(node.Value)
+    case *ast.WhileStatement:
+      walk(node.Condition)
+      walk(node.Body)
+    case *ast.ForStatement:
+      walk(node.Variable)
+      walk(node.Iterable)
+      walk(node.Body)
+    case *ast.TryExpression:
+      walk(node.Block)
+      if node.Param != nil {
+        walk(node.Param)
+      }
+      if node.Catch != nil {
+        walk(node.Catch)
+      }
+      if node.Finally != nil {
+        walk(node.Finally)
+      }
+    case *ast.HashLiteral:
+      for _, pair := range node.Pairs {
+        walk(pair.Key)
+        walk(pair.Value)
+      }
+    }
+  }
+  walk(node)
+}
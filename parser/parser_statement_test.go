@@ -88,3 +88,39 @@ func TestReturnStatements(t *testing.T) {
     }
   }
 }
+
+/***** error positions *****/
+func TestParserErrorPositions(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected string
+  }{
+    {"let x 5;", "1:7: expected next token to be =, got INT instead"},
+    {"let x = 1;\nlet = 2;", "2:5: expected next token to be IDENT, got = instead"},
+    {"1 +\n  ;", "2:3: no prefix parse function for ; found"},
+  }
+  for _, tt := range tests {
+    p := New(lexer.New(tt.input))
+    p.ParseProgram()
+    errors := p.Errors()
+    if len(errors) == 0 {
+      t.Errorf("no parser errors for %q", tt.input)
+      continue
+    }
+    if errors[0] != tt.expected {
+      t.Errorf("wrong error. expected=%q, got=%q", tt.expected, errors[0])
+    }
+  }
+}
+
+func TestCommentsAreIgnored(t *testing.T) {
+  input := `// a counter
+let x = 1; # one
+/* let y = 2; */ let z = x / 2;`
+  p := New(lexer.New(input))
+  program := p.ParseProgram()
+  checkParserErrors(t, p)
+  if program.String() != "let x = 1;let z = (x / 2);" {
+    t.Errorf("program.String() wrong. got=%q", program.String())
+  }
+}
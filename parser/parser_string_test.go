@@ -0,0 +1,64 @@
+package parser
+
+import (
+  "testing"
+  "Monkey/lexer"
+  "Monkey/ast"
+)
+
+func TestInterpolatedString(t *testing.T) {
+  tests := []struct {
+    input         string
+    expectedParts int
+    expected      string
+  }{
+    {`"hello ${name}!"`, 3, "hello ${name}!"},
+    {`"${a}${b}"`, 5, "${a}${b}"},
+    {`"you have ${n + 1} items, ${f(x)[0]}"`, 5, "you have ${(n + 1)} items, ${(f(x)[0])}"},
+    {`"outer ${"inner ${x}"}"`, 3, "outer ${inner ${x}}"},
+  }
+
+  for _, tt := range tests {
+    p := New(lexer.New(tt.input))
+    program := p.ParseProgram()
+    checkParserErrors(t, p)
+    stmt := program.Statements[0].(*ast.ExpressionStatement)
+    str, ok := stmt.Expression.(*ast.InterpolatedString)
+    if !ok {
+      t.Fatalf("stmt.Expression is not ast.InterpolatedString. got=%T", stmt.Expression)
+    }
+    if len(str.Parts) != tt.expectedParts {
+      t.Errorf("wrong number of parts for %q. want=%d, got=%d", tt.input, tt.expectedParts, len(str.Parts))
+    }
+    for i := 0; i < len(str.Parts); i += 2 {
+      if _, ok := str.Parts[i].(*ast.StringLiteral); !ok {
+        t.Errorf("Parts[%d] is not ast.StringLiteral. got=%T", i, str.Parts[i])
+      }
+    }
+    if str.String() != tt.expected {
+      t.Errorf("str.String() wrong. expected=%q, got=%q", tt.expected, str.String())
+    }
+  }
+}
+
+func TestInterpolatedStringErrors(t *testing.T) {
+  tests := []struct {
+    input    string
+    expected string
+  }{
+    {`"a ${} b"`, "1:6: no prefix parse function for TEMPLATE_TAIL found"},
+    {`"a ${x y} b"`, "1:8: expected next token to be TEMPLATE_TAIL, got IDENT instead"},
+    {`"a ${x`, "1:7: expected next token to be TEMPLATE_TAIL, got EOF instead"},
+    {`"${1"`, "1:5: unterminated string literal"},
+    {`"abc ${ "x" } `, "1:13: unterminated string literal"},
+  }
+
+  for _, tt := range tests {
+    p := New(lexer.New(tt.input))
+    p.ParseProgram()
+    errors := p.Errors()
+    if len(errors) == 0 || errors[0] != tt.expected {
+      t.Errorf("wrong errors for %q. expected=%q, got=%q", tt.input, tt.expected, errors)
+    }
+  }
+}
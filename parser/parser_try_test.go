@@ -0,0 +1,73 @@
+package parser
+
+import (
+  "testing"
+  "Monkey/lexer"
+  "Monkey/ast"
+)
+
+func TestTryExpression(t *testing.T) {
+  tests := []struct {
+    input      string
+    hasParam   bool
+    hasCatch   bool
+    hasFinally bool
+    expected   string
+  }{
+    {"try { x } catch (e) { y }", true, true, false, "try x catch (e) y"},
+    {"try { x } catch { y }", false, true, false, "try x catch y"},
+    {"try { x } finally { z }", false, false, true, "try x finally z"},
+    {"try { x } catch (e) { y } finally { z }", true, true, true, "try x catch (e) y finally z"},
+  }
+
+  for _, tt := range tests {
+    p := New(lexer.New(tt.input))
+    program := p.ParseProgram()
+    checkParserErrors(t, p)
+    if len(program.Statements) != 1 {
+      t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+    }
+    stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+    if !ok {
+      t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+    }
+    exp, ok := stmt.Expression.(*ast.TryExpression)
+    if !ok {
+      t.Fatalf("stmt.Expression is not ast.TryExpression. got=%T", stmt.Expression)
+    }
+    if (exp.Param != nil) != tt.hasParam || (exp.Catch != nil) != tt.hasCatch || (exp.Finally != nil) != tt.hasFinally {
+      t.Errorf("wrong blocks for %q. got=%+v", tt.input, exp)
+    }
+    if exp.String() != tt.expected {
+      t.Errorf("exp.String() wrong. expected=%q, got=%q", tt.expected, exp.String())
+    }
+  }
+}
+
+func TestThrowStatement(t *testing.T) {
+  p := New(lexer.New(`throw "bad input"; 5`))
+  program := p.ParseProgram()
+  checkParserErrors(t, p)
+  if len(program.Statements) != 2 {
+    t.Fatalf("program.Statements does not contain 2 statements. got=%d", len(program.Statements))
+  }
+  stmt, ok := program.Statements[0].(*ast.ThrowStatement)
+  if !ok {
+    t.Fatalf("program.Statements[0] is not ast.ThrowStatement. got=%T", program.Statements[0])
+  }
+  if stmt.Value.String() != "bad input" {
+    t.Errorf("stmt.Value wrong. got=%q", stmt.Value.String())
+  }
+}
+
+func TestTryWithoutHandler(t *testing.T) {
+  p := New(lexer.New("try { x }; 5"))
+  p.ParseProgram()
+  diagnostics := p.Diagnostics()
+  if len(diagnostics) != 1 {
+    t.Fatalf("wrong number of diagnostics. got=%d (%v)", len(diagnostics), p.Errors())
+  }
+  if diagnostics[0].Code != TRY_WITHOUT_HANDLER || diagnostics[0].Start.String() != "1:1" {
+    t.Errorf("wrong diagnostic. got=%+v", diagnostics[0])
+  }
+}
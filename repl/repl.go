@@ -29,11 +29,16 @@ func Start(in io.Reader, out io.Writer) {
     program := p.ParseProgram()
 
     if len(p.Errors()) != 0 {
-      printParserErrors(out, p.Errors())
+      printParserErrors(out, line, p.Diagnostics())
       continue
     }
 
     evaluated := evaluator.Eval(program, env)
+    // like Python's, tracebacks of the REPL don't quote source lines.
+    if errObj, ok := evaluated.(*object.Error); ok {
+      io.WriteString(out, errObj.Traceback(""))
+      continue
+    }
     if evaluated != nil {
       io.WriteString(out, evaluated.Inspect())
       io.WriteString(out, "\n")
@@ -41,8 +46,8 @@ func Start(in io.Reader, out io.Writer) {
   }
 }
 
-func printParserErrors(out io.Writer, errors []string) {  
-  for _, msg := range errors {
-    io.WriteString(out, "\t"+msg+"\n")
- }
+func printParserErrors(out io.Writer, source string, diagnostics []parser.Diagnostic) {
+  for _, d := range diagnostics {
+    io.WriteString(out, d.Render(source))
+  }
 }
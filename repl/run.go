@@ -0,0 +1,34 @@
+// Run whole Monkey programs (script files, -e snippets, piped stdin)
+package repl
+
+import (
+  "fmt"
+  "io"
+  "Monkey/lexer"
+  "Monkey/parser"
+  "Monkey/evaluator"
+  "Monkey/object"
+)
+
+/* Run lexes, parses and evaluates a whole program named #name.
+* Parser errors and the traceback of a top-level runtime error are -..
+* written to errOut.
+* @return false if the program failed to parse or evaluated to an error. */
+func Run(name string, input string, errOut io.Writer) bool {
+  l := lexer.NewFile(name, input)
+  p := parser.New(l)
+  program := p.ParseProgram()
+
+  if len(p.Errors()) != 0 {
+    printParserErrors(errOut, input, p.Diagnostics())
+    return false
+  }
+
+  env := object.NewEnvironment()
+  evaluated := evaluator.Eval(program, env)
+  if errObj, ok := evaluated.(*object.Error); ok {
+    fmt.Fprint(errOut, errObj.Traceback(input))
+    return false
+  }
+  return true
+}
@@ -1,6 +1,7 @@
 // token/token.go
 package token
 
+import "fmt"
 
 type TokenType string
 // Note: String type is easy to debug -.. but expensive comparing to int/Byte
@@ -8,6 +9,29 @@ type TokenType string
 type Token struct {
   Type TokenType
   Literal string
+  Pos     Position  // where the token starts in the source
+  Trivia  []Token   // COMMENT tokens right before this one, see Lexer.KeepComments
+}
+
+// Line and Column start at 1, a zero Position means "unknown".
+// Column counts characters (runes), not bytes.
+type Position struct {
+  Filename string   // optional, empty for REPL input
+  Line     int
+  Column   int
+}
+
+func (p Position) IsValid() bool { return p.Line > 0 }
+
+// file:line:column, the file name is omitted when unknown.
+func (p Position) String() string {
+  if !p.IsValid() {
+    return ""
+  }
+  if p.Filename != "" {
+    return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+  }
+  return fmt.Sprintf("%d:%d", p.Line, p.Column)
 }
 
 func LookupIdent(ident string) TokenType {
@@ -26,16 +50,31 @@ var keywords = map[string]TokenType {
   "if"    : IF,
   "else"  : ELSE,
   "return": RETURN,
+  "try"   : TRY,
+  "catch" : CATCH,
+  "finally": FINALLY,
+  "throw" : THROW,
+  "while" : WHILE,
+  "break" : BREAK,
+  "continue": CONTINUE,
+  "for"   : FOR,
+  "in"    : IN,
 }
 // Token types (In monkey we've limited tokens comparing to other languages)
 const (
-  ILLEGAL = "ILLEGAL" // Unknown token/character
+  ILLEGAL = "ILLEGAL" // Unknown character, or a message like "unterminated string literal"
   EOF     = "EOF"
+  COMMENT = "COMMENT" // only as trivia, the parser never sees it
 
   // Identifiers & Literals
   IDENT   = "IDENT" // x, y...
   INT     = "INT"   // 1,2,3
+  FLOAT   = "FLOAT" // 3.14, 1e-9
   STRING  = "STRING"
+  // "a ${x} b ${y} c" lexes as TEMPLATE_HEAD("a ") x TEMPLATE_MIDDLE(" b ") y TEMPLATE_TAIL(" c")
+  TEMPLATE_HEAD   = "TEMPLATE_HEAD"
+  TEMPLATE_MIDDLE = "TEMPLATE_MIDDLE"
+  TEMPLATE_TAIL   = "TEMPLATE_TAIL"
   // Operators
   ASSIGN  = "="
   PLUS    = "+"
@@ -47,15 +86,29 @@ const (
   GT      = ">"
   EQ      = "=="
   NOT_EQ  = "!="
+  LT_EQ   = "<="
+  GT_EQ   = ">="
+  AND     = "&&"
+  OR      = "||"
+  PLUS_ASSIGN     = "+="
+  MINUS_ASSIGN    = "-="
+  ASTERISK_ASSIGN = "*="
+  SLASH_ASSIGN    = "/="
 
   // Delimiters
   COMMA     = ","
   SEMICOLON = ";"
+  COLON     = ":"
+  ELLIPSIS  = "..."
+  RANGE     = ".."  // inclusive range, 0..n
+  RANGE_EXCLUSIVE = "..<" // 0..<n
 
   LPAREN  = "("
   RPAREN  = ")"
   LBRACE  = "{"
   RBRACE  = "}"
+  LBRACKET = "["
+  RBRACKET = "]"
 
   // Keywords
   FUNCTION = "FUNCTION" // Function declaration
@@ -65,6 +118,15 @@ const (
   IF       = "IF"
   ELSE     = "ELSE"
   RETURN   = "RETURN"
+  TRY      = "TRY"
+  CATCH    = "CATCH"
+  FINALLY  = "FINALLY"
+  THROW    = "THROW"
+  WHILE    = "WHILE"
+  BREAK    = "BREAK"
+  CONTINUE = "CONTINUE"
+  FOR      = "FOR"
+  IN       = "IN"
 )
 
 